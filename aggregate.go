@@ -194,7 +194,59 @@ func (c *Client) setGroups(types, field string, i []F) *Client {
 	return c
 }
 
+//SubGroup a terms bucket aggregation under the group parent, the buckets of parent are grouped again.
+// e.g Group("country").SubGroup("group_country", "city").Avg("price", "group_city")
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-aggregations.html#_structuring_aggregations
+func (c *Client) SubGroup(parent, field string, i ...F) *Client {
+	return c.setSubGroup(parent, "terms", field, i)
+}
+
+func (c *Client) setSubGroup(parent, types, field string, i []F) *Client {
+	g := c.findGroup(c.groups, parent)
+	if g == nil {
+		c.Error = fmt.Errorf("sub group on %s, but it is not existed", parent)
+		return c
+	}
+
+	_set := F{}
+	if len(i) > 0 {
+		_set = i[0]
+	}
+	if field != "" {
+		_set["field"] = field
+	}
+	if g["aggs"] == nil {
+		g["aggs"] = F{}
+	}
+	g["aggs"].(F)["group_"+field] = F{
+		types: _set,
+	}
+	return c
+}
+
+// findGroup finds the group name in groups and their sub groups
+func (c *Client) findGroup(groups F, name string) F {
+	if g, ok := groups[name].(F); ok {
+		return g
+	}
+	for _, v := range groups {
+		if g, ok := v.(F); ok {
+			if aggs, ok := g["aggs"].(F); ok {
+				if found := c.findGroup(aggs, name); found != nil {
+					return found
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Client) setMetrics(types, field string, i []interface{}) *Client {
+	return c.setMetricsAs("metric_"+field, types, field, i)
+}
+
+// setMetricsAs makes a metric aggregation named name
+func (c *Client) setMetricsAs(name, types, field string, i []interface{}) *Client {
 	var onGroup string
 	_set := F{}
 	for _, v := range i {
@@ -211,23 +263,23 @@ func (c *Client) setMetrics(types, field string, i []interface{}) *Client {
 	}
 
 	if onGroup == "*" || onGroup == "" {
-		c.metrics["metric_"+field] = F{
+		c.metrics[name] = F{
 			types: _set,
 		}
 		return c
 	}
 	// add aggregation on group aggregation
-	if c.groups[onGroup] == nil {
+	g := c.findGroup(c.groups, onGroup)
+	if g == nil {
 		c.Error = fmt.Errorf("metrics on %s, but it is not existed", onGroup)
 		return c
 	}
 
-	g := c.groups[onGroup].(F)
 	if g["aggs"] == nil {
 		g["aggs"] = F{}
 	}
 
-	g["aggs"].(F)["metric_"+field] = F{
+	g["aggs"].(F)[name] = F{
 		types: _set,
 	}
 
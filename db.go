@@ -1,6 +1,7 @@
 package esql
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -26,16 +27,29 @@ type Client struct {
 	joins    F
 	dismax   F // similar with boolQuery, but is parent of bool
 	bools    F // boolQuery
+	boolOpts F // the other settings of bool query read by ParseQuery, e.g minimum_should_match
 
 	must    []F   //where have to assigned
 	should  []F   //or and where default match
 	filter  []F   //where
 	mustnot []Not //not
 
+	postFilter []F   //post_filter, filter hits after aggregations
+	postNot    []Not //must_not of post_filter
+
+	sorts []interface{} //sort in order
+
 	aggregations F
 	metrics      F
 	groups       F //as bucket aggregation
 
+	name    string     //name of the clauses made by next builder call
+	selects *sqlSelect //plan of Query, to read response as table
+	strict  bool       //validate fields against mapping, see Strict
+
+	ctx  context.Context //context of requests, see WithContext
+	keep bool            //keep the response of FindEach, see KeepResponse
+
 	Error    error
 	queries  url.Values //query in path
 	template string     //final json data
@@ -73,7 +87,13 @@ func (c *Client) Source(i interface{}) *Client {
 
 // Timeout  10, 10ms ; or 5s & 5000 （5seconds）
 func (c *Client) Timeout(out string) *Client {
-	c.queries.Add("timeout", out)
+	c.queries.Set("timeout", out)
+	return c
+}
+
+// WithContext the requests of client are canceled with ctx
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c.ctx = ctx
 	return c
 }
 
@@ -102,14 +122,11 @@ func (c *Client) Indices() *Client {
 	return c.exec(c.hostDB.String())
 }
 
-//MakeQuery prepares query body
-func (c *Client) Serialize() *Client {
-	if c.template != "" {
-		c.Error = fmt.Errorf("make-query process more than once")
-		return c
-	}
-	_search := F{}
+//Query returns the query clause made of settings, it allows a client to be nested in other queries.
+// e.g Nested("As3", DB("").Term(F{"As3.Name": "go"}).Query())
+func (c *Client) Query() F {
 	_bool := F{}
+	_bool.Append(c.boolOpts)
 	if len(c.must) > 0 {
 		_bool["must"] = c.must
 	}
@@ -128,16 +145,50 @@ func (c *Client) Serialize() *Client {
 	//https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-dis-max-query.html
 	if len(c.dismax) > 0 {
 		c.dismax["queries"] = F{"bool": _bool}
-		_search["query"] = F{"dis_max": c.dismax}
+		return F{"dis_max": c.dismax}
 	} else if len(_bool) > 0 {
-		_search["query"] = F{"bool": _bool}
+		return F{"bool": _bool}
 	}
+	return nil
+}
 
+// searchQuery the query of request, Query in the join of Joins
+func (c *Client) searchQuery() F {
+	query := c.Query()
 	if len(c.joins) > 0 {
-		c.joins["query"] = _search["query"]
-		c.search["query"] = F{c.joinType: c.joins}
-	} else if len(_search) > 0 {
-		c.search["query"] = _search["query"]
+		c.joins["query"] = query
+		return F{c.joinType: c.joins}
+	}
+	return query
+}
+
+//MakeQuery prepares query body
+func (c *Client) Serialize() *Client {
+	if c.Error != nil {
+		return c
+	}
+	if c.template != "" {
+		c.Error = fmt.Errorf("make-query process more than once")
+		return c
+	}
+
+	if query := c.searchQuery(); query != nil {
+		c.search["query"] = query
+	}
+
+	if len(c.postFilter) > 0 || len(c.postNot) > 0 {
+		_bool := F{}
+		if len(c.postFilter) > 0 {
+			_bool["filter"] = c.postFilter
+		}
+		if len(c.postNot) > 0 {
+			_bool["must_not"] = c.postNot
+		}
+		c.search["post_filter"] = F{"bool": _bool}
+	}
+
+	if len(c.sorts) > 0 {
+		c.search["sort"] = c.sorts
 	}
 
 	c.aggregations.Append(c.metrics)
@@ -148,32 +199,35 @@ func (c *Client) Serialize() *Client {
 	}
 
 	data, err := json.Marshal(c.search)
+	if err == nil && c.strict {
+		err = c.validate(data)
+	}
 	c.template, c.Error = string(data), err
 	//clear memory, that also means the Settings of client have been locked.
 	c.clear()
 	return c
 }
 
-func (c *Client) exec(uri string, data ...string) *Client {
-	if c.Error != nil {
-		return c
+// uri returns the address with queries in path, keys limit the queries to what the api supports,
+// all queries by default.
+func (c *Client) uri(keys ...string) string {
+	_url := *c.hostDB
+	val := c.queries
+	if len(keys) > 0 {
+		val = url.Values{}
+		for _, k := range keys {
+			if v, ok := c.queries[k]; ok {
+				val[k] = v
+			}
+		}
 	}
+	_url.RawQuery = val.Encode()
+	return _url.String()
+}
 
-	if len(data) == 0 {
-		data = []string{""}
-	}
-
-	req, err := http.NewRequest(c.method, uri, strings.NewReader(data[0]))
-	if err != nil {
-		c.Error = err
-		return c
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		c.Error = err
+func (c *Client) exec(uri string, data ...string) *Client {
+	resp := c.send(uri, data...)
+	if resp == nil {
 		return c
 	}
 
@@ -195,8 +249,38 @@ func (c *Client) exec(uri string, data ...string) *Client {
 	return c
 }
 
+// send sends the request, the body of response should be closed by caller. it returns nil on error.
+func (c *Client) send(uri string, data ...string) *http.Response {
+	if c.Error != nil {
+		return nil
+	}
+
+	if len(data) == 0 {
+		data = []string{""}
+	}
+
+	req, err := http.NewRequest(c.method, uri, strings.NewReader(data[0]))
+	if err != nil {
+		c.Error = err
+		return nil
+	}
+
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.Error = err
+		return nil
+	}
+	return resp
+}
+
 func (c *Client) clear() *Client {
-	c.dismax, c.bools, c.joins, c.metrics, c.groups, c.aggregations = nil, nil, nil, nil, nil, nil
-	c.must, c.mustnot, c.should, c.filter = nil, nil, nil, nil
+	c.dismax, c.bools, c.boolOpts, c.joins, c.metrics, c.groups, c.aggregations = nil, nil, nil, nil, nil, nil, nil
+	c.must, c.mustnot, c.should, c.filter, c.sorts = nil, nil, nil, nil, nil
+	c.postFilter, c.postNot = nil, nil
 	return c
 }
@@ -3,6 +3,8 @@ package esql
 import (
 	"encoding/json"
 	"path"
+	"reflect"
+	"strings"
 )
 
 // GetDocWithID twitter/_doc/0?_source=false
@@ -20,7 +22,7 @@ func (c *Client) UpdateDoc(id string, i interface{}) *Client {
 	if checkIndexName(c) {
 		return c
 	}
-	data, _ := json.Marshal(i)
+	data, _ := docSource(i)
 	c.method = "PUT"
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_doc", id)
 	return c.exec(c.hostDB.String(), string(data))
@@ -31,7 +33,7 @@ func (c *Client) IndexDoc(id string, i interface{}) *Client {
 	if checkIndexName(c) {
 		return c
 	}
-	data, _ := json.Marshal(i)
+	data, _ := docSource(i)
 	c.method = "PUT"
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_doc", id)
 	return c.exec(c.hostDB.String(), string(data))
@@ -43,7 +45,7 @@ func (c *Client) UpdatePartialDoc(id string, i interface{}) *Client {
 	if checkIndexName(c) {
 		return c
 	}
-	data, _ := json.Marshal(i)
+	data, _ := docSource(i)
 	c.method = "POST"
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_doc", id, "_update")
 	return c.exec(c.hostDB.String(), string(data))
@@ -52,7 +54,7 @@ func (c *Client) UpdatePartialDoc(id string, i interface{}) *Client {
 // AutoIndexDocs The index operation automatically creates an index if it has not been created before
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/docs-index_.html#_automatic_id_generation
 func (c *Client) AutoIndexDoc(i interface{}) *Client {
-	data, _ := json.Marshal(i)
+	data, _ := docSource(i)
 	c.method = "POST"
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_doc")
 	return c.exec(c.hostDB.String(), string(data))
@@ -79,3 +81,47 @@ func (c *Client) DeleteByQuerry() *Client {
 	c.Serialize()
 	return c.exec(c.hostDB.String(), c.template)
 }
+
+// docSource the _source of document i, the fields of struct tagged with the metadata of hit
+// (e.g esql:"_id") are not in it, see Hit.Decode
+func docSource(i interface{}) ([]byte, error) {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return data, nil
+	}
+	keys := []string{}
+	for n := 0; n < v.NumField(); n++ {
+		fd := v.Type().Field(n)
+		if _, ok := metaFields[fd.Tag.Get("esql")]; !ok {
+			continue
+		}
+		key := strings.Split(fd.Tag.Get("json"), ",")[0]
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			key = fd.Name
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return data, nil
+	}
+
+	var source map[string]json.RawMessage
+	if err := json.Unmarshal(data, &source); err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		delete(source, k)
+	}
+	return json.Marshal(source)
+}
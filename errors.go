@@ -1,5 +1,10 @@
 package esql
 
+import "errors"
+
+// ErrNotFound returned by First and Take when no document matches
+var ErrNotFound = errors.New("esql: record not found")
+
 type Error struct {
 	Status int
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -82,10 +83,10 @@ type Setting interface {
 type F map[string]interface{}
 type Not map[string]interface{}
 
-//Fields make self as Settings
+//Fields make self as Settings, in order of field name
 func (n Not) Fields() (arr []interface{}) {
-	for k, v := range n {
-		arr = append(arr, Not{k: v})
+	for _, k := range sortedKeys(n) {
+		arr = append(arr, Not{k: n[k]})
 	}
 	return
 }
@@ -97,14 +98,23 @@ func (n Not) Append(s F) {
 	}
 }
 
-//Fields make self as Settings
+//Fields make self as Settings, in order of field name
 func (f F) Fields() (arr []interface{}) {
-	for k, v := range f {
-		arr = append(arr, F{k: v})
+	for _, k := range sortedKeys(f) {
+		arr = append(arr, F{k: f[k]})
 	}
 	return
 }
 
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 //Append copy arr's k/v struct to m
 func (f F) Append(s F) {
 	for k, v := range s {
@@ -0,0 +1,170 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Explanation how the score of a document is computed
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-explain.html
+type Explanation struct {
+	Value       float64       `json:"value"`
+	Description string        `json:"description"`
+	Details     []Explanation `json:"details"`
+}
+
+// ExplainResult the response of ExplainDoc
+type ExplainResult struct {
+	Index       string       `json:"_index"`
+	Type        string       `json:"_type"`
+	ID          string       `json:"_id"`
+	Matched     bool         `json:"matched"`
+	Explanation *Explanation `json:"explanation"`
+}
+
+// Profile the timings of search components on every shard
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-profile.html
+type Profile struct {
+	Shards []ShardProfile `json:"shards"`
+}
+
+// ShardProfile the profile of a shard, id format: [nodeID][indexName][shardID]
+type ShardProfile struct {
+	ID           string          `json:"id"`
+	Searches     []SearchProfile `json:"searches"`
+	Aggregations []ProfileNode   `json:"aggregations"`
+}
+
+// SearchProfile the profile of query tree and collectors
+type SearchProfile struct {
+	Query       []ProfileNode      `json:"query"`
+	RewriteTime int64              `json:"rewrite_time"`
+	Collector   []CollectorProfile `json:"collector"`
+}
+
+// ProfileNode the timing of a lucene query or an aggregation, with its children
+type ProfileNode struct {
+	Type        string           `json:"type"`
+	Description string           `json:"description"`
+	TimeInNanos int64            `json:"time_in_nanos"`
+	Breakdown   map[string]int64 `json:"breakdown"`
+	Children    []ProfileNode    `json:"children"`
+}
+
+// CollectorProfile the timing of a lucene collector
+type CollectorProfile struct {
+	Name        string             `json:"name"`
+	Reason      string             `json:"reason"`
+	TimeInNanos int64              `json:"time_in_nanos"`
+	Children    []CollectorProfile `json:"children"`
+}
+
+// Explain returns the explanation of score with every hit. see Hit.Explanation
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-explain.html
+func (c *Client) Explain(on bool) *Client {
+	c.search["explain"] = on
+	return c
+}
+
+// Profile returns the detailed timing of query execution. see SearchResult.Profile
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-profile.html
+func (c *Client) Profile(on bool) *Client {
+	c.search["profile"] = on
+	return c
+}
+
+// ExplainDoc explains how the query of client computes the score of document id, or why it does not match.
+// Response(&ExplainResult{}) to get the result.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-explain.html
+func (c *Client) ExplainDoc(id string) *Client {
+	if checkIndexName(c) {
+		return c
+	}
+
+	query := c.searchQuery()
+	if query == nil {
+		query = F{"match_all": F{}}
+	}
+	data, err := json.Marshal(F{"query": query})
+	if err == nil && c.strict {
+		err = c.validate(data)
+	}
+	c.template, c.Error = string(data), err
+	c.clear()
+	c.hostDB.Path = path.Join(c.hostDB.Path, "_doc", id, "_explain")
+	return c.exec(c.hostDB.String(), c.template)
+}
+
+// Report renders the explanation as an indented text, every detail is indented under its parent.
+// e.g  1.2 = weight(Name:go in 0) [PerFieldSimilarity], result of:
+func (e *Explanation) Report() string {
+	var b strings.Builder
+	e.report(&b, 0)
+	return b.String()
+}
+
+func (e *Explanation) report(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%g = %s\n", strings.Repeat("  ", depth), e.Value, e.Description)
+	for i := range e.Details {
+		e.Details[i].report(b, depth+1)
+	}
+}
+
+// Report renders the timings of every shard as an indented text
+func (p *Profile) Report() string {
+	var b strings.Builder
+	for _, shard := range p.Shards {
+		fmt.Fprintf(&b, "shard %s\n", shard.ID)
+		for _, search := range shard.Searches {
+			b.WriteString("  query:\n")
+			for _, v := range search.Query {
+				v.report(&b, 2)
+			}
+			fmt.Fprintf(&b, "  rewrite: %s\n", time.Duration(search.RewriteTime))
+			b.WriteString("  collector:\n")
+			for _, v := range search.Collector {
+				v.report(&b, 2)
+			}
+		}
+		if len(shard.Aggregations) > 0 {
+			b.WriteString("  aggregations:\n")
+			for _, v := range shard.Aggregations {
+				v.report(&b, 2)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (n *ProfileNode) report(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s %s [%s]\n", strings.Repeat("  ", depth), n.Type, time.Duration(n.TimeInNanos), n.Description)
+	for i := range n.Children {
+		n.Children[i].report(b, depth+1)
+	}
+}
+
+func (n *CollectorProfile) report(b *strings.Builder, depth int) {
+	fmt.Fprintf(b, "%s%s %s [%s]\n", strings.Repeat("  ", depth), n.Name, time.Duration(n.TimeInNanos), n.Reason)
+	for i := range n.Children {
+		n.Children[i].report(b, depth+1)
+	}
+}
+
+// Report renders the explanation of every hit and the profile as an indented text, for logs and cli.
+func (r *SearchResult) Report() string {
+	var b strings.Builder
+	for _, v := range r.Hits {
+		if v.Explanation == nil {
+			continue
+		}
+		fmt.Fprintf(&b, "hit %s/%s score %g\n", v.Index, v.ID, v.Score)
+		v.Explanation.report(&b, 1)
+	}
+	if r.Profile != nil {
+		b.WriteString(r.Profile.Report())
+	}
+	return b.String()
+}
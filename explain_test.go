@@ -0,0 +1,52 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestExplainDoc(t *testing.T) {
+	es.DB().IndexDoc("explain1", mysql{Name: "explain this document", Number: 3})
+	defer es.DB().DeleteDoc("explain1")
+
+	var got esql.ExplainResult
+	c := es.DB().Where(esql.F{"Name": "explain"}).ExplainDoc("explain1")
+	if c.Response(&got); c.Error != nil || !got.Matched {
+		t.Fatal(c.Error, got)
+	}
+	t.Log(got.Explanation.Report())
+
+	got = esql.ExplainResult{}
+	es.DB().Where(esql.F{"Name": "nothing"}).ExplainDoc("explain1").Response(&got)
+	if got.Matched {
+		t.Fatal("should not match")
+	}
+
+	// the query is in the join, the document has no As3
+	got = esql.ExplainResult{}
+	es.DB().Where(esql.F{"Name": "explain"}).Joins("nested", "As3").ExplainDoc("explain1").Response(&got)
+	if got.Matched {
+		t.Fatal("should not match in nested")
+	}
+}
+
+func TestExplainAndProfile(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(mysql{Name: "this is a test", Level: 1, Number: 1})
+
+	var result esql.SearchResult
+	c := es.DB().Where(esql.F{"Name": "test"}).Term(esql.F{"Number": 1}).
+		Explain(true).Profile(true).Count("Number").Find(&result)
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+
+	if len(result.Hits) != 1 || result.Hits[0].Explanation == nil {
+		t.Fatal(result.Hits)
+	}
+	if result.Profile == nil || len(result.Profile.Shards) == 0 {
+		t.Fatal("no profile")
+	}
+	t.Log(result.Report())
+}
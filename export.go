@@ -0,0 +1,142 @@
+package esql
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// the formats of Export
+const (
+	// NDJSON a line of _source for every hit
+	NDJSON = "ndjson"
+	// NDJSONMeta a line of {"_index", "_id", "_score", "_source"} for every hit
+	NDJSONMeta = "ndjson_meta"
+	// CSV a header line of columns, then a line for every hit
+	CSV = "csv"
+)
+
+// ExportBatch the size of scroll batches of Export
+var ExportBatch = 1000
+
+// Export writes all hits of the query to w in format by scroll, batch by batch.
+// columns are the ordered columns of CSV, a column is a dotted path of _source, e.g "address.city",
+// or the metadata of hit: _id, _index, _type, _score, _version, _seq_no, _primary_term.
+// the values of arrays are joined with ";", objects are json. the paths of the first hit are the columns by default.
+// e.g es.DB().Term(F{"Level": 1}).Export(os.Stdout, CSV, "_id", "Name", "Golang.First")
+func (c *Client) Export(w io.Writer, format string, columns ...string) error {
+	var write func(hits []Hit) error
+	switch format {
+	case NDJSON, NDJSONMeta:
+		write = func(hits []Hit) error {
+			return writeNDJSON(w, format, hits)
+		}
+	case CSV:
+		cw := csv.NewWriter(w)
+		header := len(columns) > 0
+		if header {
+			// the header is written even if no hit
+			cw.Write(columns)
+			if cw.Flush(); cw.Error() != nil {
+				c.Error = cw.Error()
+				return c.Error
+			}
+		}
+		write = func(hits []Hit) error {
+			for _, h := range hits {
+				source := decodeSource(h.Source)
+				if !header {
+					if len(columns) == 0 {
+						m, _ := source.(map[string]interface{})
+						columns = sourcePaths("", m)
+					}
+					if err := cw.Write(columns); err != nil {
+						return err
+					}
+					header = true
+				}
+
+				record := make([]string, len(columns))
+				for i, col := range columns {
+					if meta, ok := metaFields[col]; ok {
+						record[i] = csvValue(meta(h))
+						continue
+					}
+					record[i] = csvValue(sourceValue(source, col))
+				}
+				if err := cw.Write(record); err != nil {
+					return err
+				}
+			}
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		c.Error = fmt.Errorf("esql: unknown export format %s", format)
+		return c.Error
+	}
+
+	return c.ScrollAll(ExportBatch, "1m", write)
+}
+
+func writeNDJSON(w io.Writer, format string, hits []Hit) error {
+	var buf bytes.Buffer
+	for _, h := range hits {
+		line := []byte(h.Source)
+		if len(line) == 0 {
+			// the hit without _source, e.g Source(false)
+			line = []byte("{}")
+		}
+		if format == NDJSONMeta {
+			doc := F{"_index": h.Index, "_id": h.ID, "_score": h.Score}
+			if len(h.Source) > 0 {
+				doc["_source"] = h.Source
+			}
+			if h.Type != "" {
+				doc["_type"] = h.Type
+			}
+			var err error
+			if line, err = json.Marshal(doc); err != nil {
+				return err
+			}
+		}
+		// the raw _source may have new lines
+		if err := json.Compact(&buf, line); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// decodeSource decodes _source with the numbers as they are
+func decodeSource(data json.RawMessage) interface{} {
+	var source interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.Decode(&source)
+	return source
+}
+
+func csvValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case []interface{}:
+		arr := make([]string, len(value))
+		for i, e := range value {
+			arr[i] = csvValue(e)
+		}
+		return strings.Join(arr, ";")
+	case map[string]interface{}:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
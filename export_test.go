@@ -0,0 +1,53 @@
+package esql_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestExport(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		employee{Name: "rob, pike", Number: 1, Golang: golang{First: "rob"}, As3: []as3{{Name: "a"}, {Name: "b"}}},
+		employee{Name: "ken", Number: 1},
+	)
+
+	var buf bytes.Buffer
+	if err := es.DB().Term(esql.F{"Number": 1}).Export(&buf, esql.CSV, "Name", "Golang.First", "As3.Name"); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || lines[0] != "Name,Golang.First,As3.Name" {
+		t.Fatal(buf.String())
+	}
+	if !strings.Contains(buf.String(), `"rob, pike",rob,a;b`) {
+		t.Fatal(buf.String())
+	}
+
+	buf.Reset()
+	if err := es.DB().Term(esql.F{"Number": 1}).Export(&buf, esql.NDJSONMeta); err != nil {
+		t.Fatal(err)
+	}
+	if lines = strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[0], `"_id"`) {
+		t.Fatal(buf.String())
+	}
+
+	// no hit, the header of columns is written
+	buf.Reset()
+	if err := es.DB().Term(esql.F{"Number": 404}).Export(&buf, esql.CSV, "Name", "_id"); err != nil || buf.String() != "Name,_id\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	// the hits without _source
+	buf.Reset()
+	if err := es.DB().Term(esql.F{"Number": 1}).Source(false).Export(&buf, esql.NDJSON); err != nil || buf.String() != "{}\n{}\n" {
+		t.Fatal(err, buf.String())
+	}
+
+	if err := es.DB().Export(&buf, "xml"); err == nil {
+		t.Fatal("xml is not supported")
+	}
+}
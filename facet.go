@@ -0,0 +1,49 @@
+package esql
+
+// Facet a terms facet of faceted navigation
+type Facet struct {
+	Field string
+	// the values user selected, hits are filtered by them
+	Selected []interface{}
+	// settings of terms aggregation, e.g F{"size": 20}
+	Setting F
+}
+
+// Facets makes faceted navigation: hits are filtered by the selected values of all facets through post_filter,
+// and every facet counts its buckets under the selections of the other facets, but not its own selection.
+// the buckets of facet are in aggregations.facet_{field}.group_{field}
+// e.g  Facets(Facet{Field: "color", Selected: []interface{}{"red"}}, Facet{Field: "brand"})
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-post-filter.html
+func (c *Client) Facets(facets ...Facet) *Client {
+	for _, v := range facets {
+		if len(v.Selected) > 0 {
+			c.postFilter = append(c.postFilter, F{"terms": F{v.Field: v.Selected}})
+		}
+	}
+
+	if c.aggregations == nil {
+		c.aggregations = F{}
+	}
+	for _, v := range facets {
+		filters := []F{}
+		for _, other := range facets {
+			if other.Field != v.Field && len(other.Selected) > 0 {
+				filters = append(filters, F{"terms": F{other.Field: other.Selected}})
+			}
+		}
+
+		filter := F{"match_all": F{}}
+		if len(filters) > 0 {
+			filter = F{"bool": F{"filter": filters}}
+		}
+
+		_set := F{}
+		_set.Append(v.Setting)
+		_set["field"] = v.Field
+		c.aggregations["facet_"+v.Field] = F{
+			"filter": filter,
+			"aggs":   F{"group_" + v.Field: F{"terms": _set}},
+		}
+	}
+	return c
+}
@@ -0,0 +1,64 @@
+package esql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestPostFilter(t *testing.T) {
+	c := es.DB().Where(esql.F{"Name": "test"}).
+		PostFilter("term", esql.F{"Level": 1}, esql.Not{"Number": 2}).
+		PostFilter("range", esql.F{"Age": esql.F{"gt": 18}}).
+		Group("Level")
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+
+	// the settings are cleared after Serialize
+	if c.Facets(esql.Facet{Field: "Level"}).Serialize().Error == nil {
+		t.Fatal("serialize more than once")
+	}
+}
+
+func TestFacets(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "red", Level: 1, Number: 1},
+		mysql{Name: "red", Level: 2, Number: 1},
+		mysql{Name: "blue", Level: 1, Number: 1},
+	)
+
+	var result struct {
+		Hits struct {
+			Hits []interface{} `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]json.RawMessage `json:"aggregations"`
+	}
+	c := es.DB().Term(esql.F{"Number": 1}).Facets(
+		esql.Facet{Field: "Level", Selected: []interface{}{1}},
+		esql.Facet{Field: "Description", Setting: esql.F{"size": 5}},
+	).Find(&result)
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	t.Log(c.Template())
+
+	if len(result.Hits.Hits) != 2 {
+		t.Fatal(len(result.Hits.Hits))
+	}
+
+	var level struct {
+		DocCount int `json:"doc_count"`
+		Group    struct {
+			Buckets []interface{} `json:"buckets"`
+		} `json:"group_Level"`
+	}
+	json.Unmarshal(result.Aggregations["facet_Level"], &level)
+	// the selection of Level does not affect its own buckets
+	if level.DocCount != 3 || len(level.Group.Buckets) != 2 {
+		t.Fatal(string(result.Aggregations["facet_Level"]))
+	}
+}
@@ -0,0 +1,86 @@
+package esql
+
+import "encoding/json"
+
+// First finds the first document into i, e.g &struct{}, ErrNotFound is the Error if no document matches.
+// the documents are in order of the sorts of client, or the fields of orderBy (asc), or index order (_doc).
+// e.g es.DB().Term(F{"Level": 1}).First(&record, "JoinDate")
+func (c *Client) First(i interface{}, orderBy ...string) *Client {
+	if len(c.sorts) == 0 {
+		if len(orderBy) == 0 {
+			orderBy = []string{"_doc"}
+		}
+		for _, v := range orderBy {
+			c.ThenBy(v, Asc)
+		}
+	}
+	return c.Take(i)
+}
+
+// Take finds a document into i without order, ErrNotFound is the Error if no document matches.
+func (c *Client) Take(i interface{}) *Client {
+	var result SearchResult
+	if c.Limit(1).Find(&result).Error != nil {
+		return c
+	}
+	if len(result.Hits) == 0 {
+		c.Error = ErrNotFound
+		return c
+	}
+	c.Error = result.Hits[0].Decode(i)
+	return c
+}
+
+// Pluck collects the values of field of the hits into i, i is a pointer to slice. only the field is requested
+// by _source, for the fields not in _source (e.g multi fields), set DocvalueFields(field) firstly.
+// e.g var names []string; es.DB().Term(F{"Level": 1}).Limit(100).Pluck("Name", &names)
+func (c *Client) Pluck(field string, i interface{}) *Client {
+	if c.docvalueField(field) {
+		c.Source(false)
+	} else {
+		c.Source([]string{field})
+	}
+
+	var result SearchResult
+	if c.Find(&result).Error != nil {
+		return c
+	}
+
+	values := []interface{}{}
+	for _, h := range result.Hits {
+		var source interface{}
+		json.Unmarshal(h.Source, &source)
+		value := sourceValue(source, field)
+		if fields, ok := h.Fields[field]; value == nil && ok {
+			value = fields
+			if len(fields) == 1 {
+				value = fields[0]
+			}
+		}
+		values = append(values, value)
+	}
+
+	data, _ := json.Marshal(values)
+	c.Error = json.Unmarshal(data, i)
+	return c
+}
+
+// docvalueField reports whether field is one of DocvalueFields
+func (c *Client) docvalueField(field string) bool {
+	arr, _ := c.search["docvalue_fields"].([]interface{})
+	for _, v := range arr {
+		if v == field {
+			return true
+		}
+		if _set, ok := v.(F); ok && _set["field"] == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Exists reports whether any document matches the query, the counting terminates after the first one.
+func (c *Client) Exists() (bool, error) {
+	n, err := c.TerminateAfter(1).CountDocs()
+	return n > 0, err
+}
@@ -0,0 +1,60 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestFinders(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 2, Number: 1},
+		mysql{Name: "another one", Level: 1, Number: 1},
+		mysql{Name: "the last", Level: 3, Number: 1},
+	)
+
+	var first mysql
+	if err := es.DB().Term(esql.F{"Number": 1}).First(&first, "Level").Error; err != nil {
+		t.Fatal(err)
+	}
+	if first.Level != 1 || first.Name != "another one" {
+		t.Fatal(first)
+	}
+
+	var last mysql
+	if err := es.DB().Term(esql.F{"Number": 1}).OrderBy("Level", esql.Desc).First(&last).Error; err != nil || last.Level != 3 {
+		t.Fatal(err, last)
+	}
+
+	var any mysql
+	if err := es.DB().Term(esql.F{"Number": 1}).Take(&any).Error; err != nil || any.Number != 1 {
+		t.Fatal(err, any)
+	}
+	if err := es.DB().Term(esql.F{"Number": 404}).Take(&any).Error; err != esql.ErrNotFound {
+		t.Fatal(err)
+	}
+
+	var levels []int
+	if err := es.DB().Term(esql.F{"Number": 1}).OrderBy("Level").Pluck("Level", &levels).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 3 || levels[0] != 1 || levels[2] != 3 {
+		t.Fatal(levels)
+	}
+
+	// the field is from _source, the doc values of other fields are not the values
+	var names []string
+	err := es.DB().Term(esql.F{"Number": 1}).DocvalueFields("Level").OrderBy("Level").Pluck("Name", &names).Error
+	if err != nil || len(names) != 3 || names[0] == "" {
+		t.Fatal(err, names)
+	}
+
+	ok, err := es.DB().Term(esql.F{"Number": 1}).Exists()
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	if ok, _ = es.DB().Term(esql.F{"Number": 404}).Exists(); ok {
+		t.Fatal("no document of Number 404")
+	}
+}
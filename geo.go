@@ -0,0 +1,206 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var geoPointType = reflect.TypeOf(GeoPoint{})
+
+const geohashBase32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeoPoint the value of geo_point field. it marshals as object {"lat": 41.12, "lon": -71.34},
+// and unmarshals from any format elasticsearch accepts:
+// object, array [lon, lat], string "lat,lon", geohash "drm3btev3e86" and WKT "POINT (lon lat)".
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/geo-point.html
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Array format [lon, lat], the GeoJSON order
+func (p GeoPoint) Array() []float64 {
+	return []float64{p.Lon, p.Lat}
+}
+
+// String format "lat,lon"
+func (p GeoPoint) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.Lon, 'f', -1, 64)
+}
+
+// WKT format "POINT (lon lat)"
+func (p GeoPoint) WKT() string {
+	return "POINT (" + strconv.FormatFloat(p.Lon, 'f', -1, 64) + " " + strconv.FormatFloat(p.Lat, 'f', -1, 64) + ")"
+}
+
+// UnmarshalJSON accepts all formats of geo_point
+func (p *GeoPoint) UnmarshalJSON(data []byte) error {
+	var i interface{}
+	if err := json.Unmarshal(data, &i); err != nil {
+		return err
+	}
+
+	switch v := i.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		lat, ok1 := v["lat"].(float64)
+		lon, ok2 := v["lon"].(float64)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("esql: illegal geo_point %s", data)
+		}
+		p.Lat, p.Lon = lat, lon
+	case []interface{}:
+		if len(v) < 2 {
+			return fmt.Errorf("esql: illegal geo_point %s", data)
+		}
+		lon, ok1 := v[0].(float64)
+		lat, ok2 := v[1].(float64)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("esql: illegal geo_point %s", data)
+		}
+		p.Lat, p.Lon = lat, lon
+	case string:
+		return p.parse(v)
+	default:
+		return fmt.Errorf("esql: illegal geo_point %s", data)
+	}
+	return nil
+}
+
+func (p *GeoPoint) parse(str string) (err error) {
+	str = strings.TrimSpace(str)
+	if s := strings.ToUpper(str); strings.HasPrefix(s, "POINT") {
+		a := strings.Fields(strings.Trim(strings.TrimSpace(s[len("POINT"):]), "()"))
+		if len(a) != 2 {
+			return fmt.Errorf("esql: illegal geo_point %s", str)
+		}
+		if p.Lon, err = strconv.ParseFloat(a[0], 64); err != nil {
+			return err
+		}
+		p.Lat, err = strconv.ParseFloat(a[1], 64)
+		return err
+	}
+
+	if a := strings.Split(str, ","); len(a) == 2 {
+		if p.Lat, err = strconv.ParseFloat(strings.TrimSpace(a[0]), 64); err != nil {
+			return err
+		}
+		p.Lon, err = strconv.ParseFloat(strings.TrimSpace(a[1]), 64)
+		return err
+	}
+
+	return p.decodeGeohash(str)
+}
+
+// the center of geohash cell
+func (p *GeoPoint) decodeGeohash(hash string) error {
+	lat, lon := [2]float64{-90, 90}, [2]float64{-180, 180}
+	even := true
+	for _, r := range strings.ToLower(hash) {
+		n := strings.IndexRune(geohashBase32, r)
+		if n < 0 {
+			return fmt.Errorf("esql: illegal geo_point %s", hash)
+		}
+		for mask := 16; mask > 0; mask >>= 1 {
+			rng := &lat
+			if even {
+				rng = &lon
+			}
+			mid := (rng[0] + rng[1]) / 2
+			if n&mask != 0 {
+				rng[0] = mid
+			} else {
+				rng[1] = mid
+			}
+			even = !even
+		}
+	}
+	p.Lat, p.Lon = (lat[0]+lat[1])/2, (lon[0]+lon[1])/2
+	return nil
+}
+
+// Envelope a shape of geo_shape, consists of coordinates for upper left and lower right points
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/geo-shape.html#_envelope
+func Envelope(topLeft, bottomRight GeoPoint) F {
+	return F{"type": "envelope", "coordinates": [][]float64{topLeft.Array(), bottomRight.Array()}}
+}
+
+// Polygon a shape of geo_shape, the first ring is outer boundary, the others are holes.
+// every ring should be closed, that the first and last points are same.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/geo-shape.html#_ulink_url_http_geojson_org_geojson_spec_html_id4_polygon_ulink
+func Polygon(rings ...[]GeoPoint) F {
+	return F{"type": "polygon", "coordinates": polygonCoordinates(rings)}
+}
+
+// MultiPolygon a shape of geo_shape, a list of polygons
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/geo-shape.html#_ulink_url_http_www_geojson_org_geojson_spec_html_id7_multipolygon_ulink
+func MultiPolygon(polygons ...[][]GeoPoint) F {
+	arr := [][][][]float64{}
+	for _, v := range polygons {
+		arr = append(arr, polygonCoordinates(v))
+	}
+	return F{"type": "multipolygon", "coordinates": arr}
+}
+
+// Circle a shape of geo_shape, radius e.g "100m"
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/geo-shape.html#_circle
+func Circle(center GeoPoint, radius string) F {
+	return F{"type": "circle", "coordinates": center.Array(), "radius": radius}
+}
+
+func polygonCoordinates(rings [][]GeoPoint) [][][]float64 {
+	arr := [][][]float64{}
+	for _, ring := range rings {
+		points := [][]float64{}
+		for _, p := range ring {
+			points = append(points, p.Array())
+		}
+		arr = append(arr, points)
+	}
+	return arr
+}
+
+// GeoPolygon https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-geo-polygon-query.html
+// e.g  GeoPolygon("address.location", GeoPoint{40, -70}, GeoPoint{30, -80}, GeoPoint{20, -90})
+func (c *Client) GeoPolygon(fieldName string, points ...GeoPoint) *Client {
+	geo := F{}
+	geo[fieldName] = F{"points": points}
+	c.filter = append(c.filter, c.named("geo_polygon", geo))
+	return c
+}
+
+// GeoShape https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-geo-shape-query.html
+// shape: Envelope, Polygon, MultiPolygon, Circle or F of any GeoJSON shape
+// relation: intersects(default), within, disjoint
+// e.g  GeoShape("location", Envelope(GeoPoint{53, 13}, GeoPoint{52, 14}), "within")
+func (c *Client) GeoShape(fieldName string, shape F, relation string) *Client {
+	if relation == "" {
+		relation = "intersects"
+	}
+	geo := F{}
+	geo[fieldName] = F{"shape": shape, "relation": relation}
+	c.filter = append(c.filter, c.named("geo_shape", geo))
+	return c
+}
+
+// OrderGeoDistance sort by distance to origin, appended after other sorts.
+// unit: km, m, mi ... ; distanceType: arc, plane ; empty string uses the default of elasticsearch
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-sort.html#geo-sorting
+func (c *Client) OrderGeoDistance(fieldName string, origin GeoPoint, order, unit, distanceType string) *Client {
+	_set := F{fieldName: origin}
+	if order != "" {
+		_set["order"] = order
+	}
+	if unit != "" {
+		_set["unit"] = unit
+	}
+	if distanceType != "" {
+		_set["distance_type"] = distanceType
+	}
+	c.sorts = append(c.sorts, F{"_geo_distance": _set})
+	return c
+}
@@ -0,0 +1,72 @@
+package esql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+type place struct {
+	Name     string
+	Location esql.GeoPoint
+	Area     esql.F `esql:"type:geo_shape"`
+}
+
+func TestGeoPoint(t *testing.T) {
+	cases := []string{
+		`{"lat": 41.12, "lon": -71.34}`,
+		`[-71.34, 41.12]`,
+		`"41.12,-71.34"`,
+		`"POINT (-71.34 41.12)"`,
+		`"drm3btev3e86"`,
+	}
+
+	for _, v := range cases {
+		var p esql.GeoPoint
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			t.Fatal(v, err)
+		}
+		if p.Lat < 41.11 || p.Lat > 41.13 || p.Lon < -71.35 || p.Lon > -71.33 {
+			t.Fatal(v, p)
+		}
+	}
+
+	data, _ := json.Marshal(esql.GeoPoint{Lat: 41.12, Lon: -71.34})
+	if string(data) != `{"lat":41.12,"lon":-71.34}` {
+		t.Fatal(string(data))
+	}
+}
+
+func TestGeoQueries(t *testing.T) {
+	c := es.DB()
+	ring := []esql.GeoPoint{{Lat: 40, Lon: -70}, {Lat: 30, Lon: -80}, {Lat: 20, Lon: -90}, {Lat: 40, Lon: -70}}
+	c.GeoPolygon("Location", ring[:3]...).
+		GeoShape("Area", esql.Envelope(esql.GeoPoint{Lat: 53, Lon: 13}, esql.GeoPoint{Lat: 52, Lon: 14}), "within").
+		GeoShape("Area", esql.MultiPolygon([][]esql.GeoPoint{ring}), "disjoint").
+		GeoShape("Area", esql.Circle(esql.GeoPoint{Lat: 40, Lon: -70}, "1km"), "").
+		Order(esql.F{"Name": "desc"}).
+		OrderGeoDistance("Location", esql.GeoPoint{Lat: 40, Lon: -70}, "asc", "km", "plane")
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(c.Template())
+}
+
+func TestGeoMapping(t *testing.T) {
+	if err := es.DB().Table("geotest").AutoMapping(place{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	defer es.DB().Table("geotest").Delete()
+
+	got := esql.F{}
+	es.DB().Table("geotest").ShowMapping().Response(&got)
+	props := got["geotest"].(map[string]interface{})["mappings"].(map[string]interface{})["_doc"].(map[string]interface{})["properties"].(map[string]interface{})
+	if props["Location"].(map[string]interface{})["type"] != "geo_point" {
+		t.Fatal(props["Location"])
+	}
+	if props["Area"].(map[string]interface{})["type"] != "geo_shape" {
+		t.Fatal(props["Area"])
+	}
+}
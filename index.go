@@ -105,6 +105,8 @@ type employee struct {
 	Gender      string    `esql:"-"`
 	Age         int       `esql:"type:integer"`
 	Location    []float64  `esql:"type:geo_point"`
+	Position    esql.GeoPoint
+	Area        F         `esql:"type:geo_shape"`
 	JoinDate    time.Time
 	//fields config: https://www.elastic.co/guide/en/elasticsearch/reference/current/multi-fields.html
 	Description string `esql:"type:text;analyzer:english;ignore_above:500;fields:name,type,analyzer"`
@@ -148,6 +150,7 @@ func (c *Client) AutoMapping(i interface{}) *Client {
 		return c
 	}
 
+	forgetMapping(c.hostDB.Path)
 	c.method = "PUT"
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_mapping/_doc")
 	c.template = mapStr
@@ -158,14 +161,32 @@ func parseStruct(t reflect.Type) F {
 	maps := F{}
 	for i := 0; i < t.NumField(); i++ {
 		fd := t.Field(i)
+		if fd.Type.Kind() == reflect.Ptr {
+			fd.Type = fd.Type.Elem()
+		}
 		ft := fd.Type.Kind()
 		tags := fd.Tag.Get("esql")
 		if tags == "-" {
 			maps[fd.Name] = F{"enabled": false}
 			continue
 		}
+		// the metadata of hit, see Hit.Decode
+		if _, ok := metaFields[tags]; ok {
+			continue
+		}
 
 		_set := parseTags(tags)
+		// op is the operator of WhereStruct, not a mapping parameter
+		delete(_set, "op")
+		if isGeoPoint(fd.Type) && _set["type"] == nil {
+			_set["type"] = "geo_point"
+		}
+		// geo values are arrays, objects or strings, they have no properties
+		if _set["type"] == "geo_point" || _set["type"] == "geo_shape" {
+			maps[fd.Name] = _set
+			continue
+		}
+
 		if fd.Type.Name() == "Duration" || strings.Contains(fd.Type.Name(), "Time") {
 			if _set["type"] == nil {
 				_set["type"] = "date"
@@ -179,20 +200,15 @@ func parseStruct(t reflect.Type) F {
 			if _set["type"] == nil {
 				_set["type"] = "object"
 			}
-		case reflect.Ptr:
-			fd.Type = fd.Type.Elem()
-			fallthrough
 		case reflect.Struct:
 			_set["properties"] = parseStruct(fd.Type)
 		case reflect.Slice:
 			if _set["type"] == nil {
 				//here will not set it's properties, caused es only index nested array!
 				_set["type"] = "object"
-			} else if _set["type"] == "geo_point" {
-				_set["type"] = "geo_point"
-			} else {
+			} else if elem := fd.Type.Elem(); elem.Kind() == reflect.Struct {
 				//should indicate exactly to nested
-				_set["properties"] = parseStruct(fd.Type.Elem())
+				_set["properties"] = parseStruct(elem)
 			}
 		case reflect.String:
 			if _set["type"] == nil {
@@ -221,6 +237,14 @@ func parseStruct(t reflect.Type) F {
 	return maps
 }
 
+// GeoPoint, *GeoPoint or []GeoPoint
+func isGeoPoint(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	return t == geoPointType
+}
+
 func parseTags(str string) F {
 	_m := F{}
 	if str == "" {
@@ -0,0 +1,55 @@
+package esql
+
+// joining queries are built as F, so they can be put into any bool clause by Clause,
+// combined with other conditions or other joins on different paths.
+// i other settings of joining query, e.g
+// F{"score_mode": "avg", "ignore_unmapped": true, "inner_hits": F{"size": 3}}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/joining-queries.html
+
+// Nested {"nested" : { "path" : "comments", "query" : {...} }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-nested-query.html
+func Nested(path string, query F, i ...F) F {
+	_set := joinSetting(query, i)
+	_set["path"] = path
+	return F{"nested": _set}
+}
+
+// HasChild {"has_child" : { "type" : "blog_tag", "query" : {...} }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-has-child-query.html
+func HasChild(childType string, query F, i ...F) F {
+	_set := joinSetting(query, i)
+	_set["type"] = childType
+	return F{"has_child": _set}
+}
+
+// HasParent {"has_parent" : { "parent_type" : "blog", "query" : {...} }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-has-parent-query.html
+func HasParent(parentType string, query F, i ...F) F {
+	_set := joinSetting(query, i)
+	_set["parent_type"] = parentType
+	return F{"has_parent": _set}
+}
+
+// ParentID {"parent_id" : { "type" : "my_child", "id" : "1" }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-parent-id-query.html
+func ParentID(childType, id string, i ...F) F {
+	_set := F{}
+	for _, v := range i {
+		_set.Append(v)
+	}
+	_set["type"], _set["id"] = childType, id
+	return F{"parent_id": _set}
+}
+
+// an empty query matches all documents
+func joinSetting(query F, i []F) F {
+	_set := F{}
+	for _, v := range i {
+		_set.Append(v)
+	}
+	if len(query) == 0 {
+		query = F{"match_all": F{}}
+	}
+	_set["query"] = query
+	return _set
+}
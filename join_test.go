@@ -0,0 +1,55 @@
+package esql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/han2015/esql"
+)
+
+func TestNestedClauses(t *testing.T) {
+	c := es.DB()
+	as3 := esql.Nested("As3", esql.DB("").Term(esql.F{"As3.Name": "go"}).Query(), esql.F{"score_mode": "avg", "inner_hits": esql.F{}})
+	mysql := esql.Nested("Mysql", nil, esql.F{"ignore_unmapped": true})
+	c.Clause("filter", as3).Clause("should", mysql).Term(esql.F{"Number": 2})
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+
+	child := esql.HasChild("answer", esql.F{"match": esql.F{"body": "go"}}, esql.F{"inner_hits": esql.F{"size": 3}})
+	parent := esql.HasParent("question", nil)
+	c = es.DB().Clause("must", child, parent, esql.ParentID("answer", "1"))
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+}
+
+func TestNestedInnerHits(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 2}).DeleteByQuerry()
+	initRecords(
+		employee{Name: "gopher", Number: 2, As3: []as3{{Name: "go"}, {Name: "rust"}}},
+		employee{Name: "rustacean", Number: 2, As3: []as3{{Name: "rust"}}},
+	)
+	defer es.DB().Term(esql.F{"Number": 2}).DeleteByQuerry()
+
+	var result esql.SearchResult
+	nested := esql.Nested("As3", esql.F{"term": esql.F{"As3.Name": "go"}}, esql.F{"inner_hits": esql.F{}})
+	if err := es.DB().Term(esql.F{"Number": 2}).Clause("filter", nested).Find(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Hits) != 1 {
+		t.Fatal(len(result.Hits))
+	}
+	inner := result.Hits[0].InnerHits["As3"]
+	if inner == nil || len(inner.Hits) != 1 {
+		t.Fatal(result.Hits[0].InnerHits)
+	}
+	var got as3
+	if err := inner.Hits[0].Decode(&got); err != nil || got.Name != "go" {
+		t.Fatal(got, err)
+	}
+	time.Sleep(time.Second)
+}
@@ -0,0 +1,118 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MultiSearcher batches the searches of clients into one _msearch request
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-multi-search.html
+type MultiSearcher struct {
+	clients []*Client
+	results []interface{}
+	queries url.Values
+}
+
+// MultiSearch the searches of clients, every client is prepared as Find, on its index.
+// after Do, the response and Error of search are in the client, read them by Response.
+// e.g m := MultiSearch(es.DB().Where(F{"Name": "go"}), esql.DB("blog").Group("tag"))
+// err := m.Bind(0, &result).Do()
+func MultiSearch(clients ...*Client) *MultiSearcher {
+	return &MultiSearcher{
+		clients: clients,
+		results: make([]interface{}, len(clients)),
+		queries: url.Values{},
+	}
+}
+
+// Bind decodes the response of the ith client into result, result should be a reflect.Ptr,
+// the hits are decoded into a pointer to slice of struct as Find
+func (m *MultiSearcher) Bind(i int, result interface{}) *MultiSearcher {
+	if i >= 0 && i < len(m.results) {
+		m.results[i] = result
+	}
+	return m
+}
+
+// MaxConcurrentSearches the maximum number of concurrent searches of the request
+func (m *MultiSearcher) MaxConcurrentSearches(n int) *MultiSearcher {
+	m.queries.Set("max_concurrent_searches", strconv.Itoa(n))
+	return m
+}
+
+// the queries of client which are in the header of search
+var msearchHeaders = []string{"routing", "preference", "search_type", "request_cache"}
+
+// Do sends the searches, the returned error is of request, the errors of searches are in the clients.
+// the clients which have Error before are skipped.
+func (m *MultiSearcher) Do() error {
+	var body strings.Builder
+	sent := []int{}
+	for i, c := range m.clients {
+		if c.Serialize().Error != nil {
+			continue
+		}
+
+		header := F{}
+		if index := strings.Trim(c.hostDB.Path, "/"); index != "" {
+			header["index"] = index
+		}
+		for _, k := range msearchHeaders {
+			if v := c.queries.Get(k); v != "" {
+				header[k] = v
+			}
+		}
+		data, _ := json.Marshal(header)
+		body.Write(data)
+		body.WriteString("\n")
+		body.WriteString(c.template)
+		body.WriteString("\n")
+		sent = append(sent, i)
+	}
+	if len(sent) == 0 {
+		return nil
+	}
+
+	db := DB("_msearch")
+	db.method, db.queries = "POST", m.queries
+	if db.exec(db.uri(), body.String()).Error != nil {
+		return db.Error
+	}
+
+	var got struct {
+		Responses []json.RawMessage `json:"responses"`
+	}
+	if db.Response(&got); db.Error != nil {
+		return db.Error
+	}
+	if len(got.Responses) != len(sent) {
+		return fmt.Errorf("esql: %d responses of %d searches", len(got.Responses), len(sent))
+	}
+
+	for n, i := range sent {
+		c, resp := m.clients[i], got.Responses[n]
+		var errs struct {
+			Error interface{} `json:"error"`
+		}
+		json.Unmarshal(resp, &errs)
+		if errs.Error != nil {
+			c.Error = fmt.Errorf("%s", resp)
+			continue
+		}
+		c.response = resp
+		if result := m.results[i]; isStructSlice(reflect.TypeOf(result)) {
+			// the same as Find, hits into the slice of struct
+			var got SearchResult
+			if c.Error = json.Unmarshal(resp, &got); c.Error == nil {
+				c.Error = got.Scan(result)
+			}
+		} else if result != nil {
+			c.Error = json.Unmarshal(resp, result)
+		}
+	}
+	return nil
+}
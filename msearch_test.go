@@ -0,0 +1,45 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestMultiSearch(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(mysql{Name: "this is a test", Level: 1, Number: 1}, mysql{Name: "another one", Level: 2, Number: 1})
+
+	var first, second esql.SearchResult
+	var counts esql.F
+	var docs []mysql
+	clients := []*esql.Client{
+		es.DB().Where(esql.F{"Name": "test"}),
+		es.DB().Term(esql.F{"Number": 1}).Routing("1"),
+		es.DB().Term(esql.F{"Number": 1}).Group("Level").Limit(0),
+		es.DB().Range(esql.F{"Level": esql.F{"foo": 1}}),
+		es.DB().Clause("unknown", esql.F{}),
+		es.DB().Term(esql.F{"Number": 1}).OrderBy("Level", esql.Asc),
+	}
+	err := esql.MultiSearch(clients...).Bind(0, &first).Bind(1, &second).Bind(5, &docs).MaxConcurrentSearches(2).Do()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clients[0].Error != nil || len(first.Hits) != 1 {
+		t.Fatal(clients[0].Error, first.Hits)
+	}
+	if clients[1].Error != nil || len(second.Hits) != 2 {
+		t.Fatal(clients[1].Error, second.Hits)
+	}
+	if clients[2].Response(&counts); clients[2].Error != nil || counts["aggregations"] == nil {
+		t.Fatal(clients[2].Error, counts)
+	}
+	if clients[5].Error != nil || len(docs) != 2 || docs[1].Name != "another one" {
+		t.Fatal(clients[5].Error, docs)
+	}
+	// the errors of search and client
+	if clients[3].Error == nil || clients[4].Error == nil {
+		t.Fatal(clients[3].Error, clients[4].Error)
+	}
+}
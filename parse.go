@@ -0,0 +1,161 @@
+package esql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ParseQuery reads a search body into the settings of a new client on all indexes, which can be
+// modified with the builders and serialized again, e.g the raw queries of Exec.
+// the clauses of bool query go to Must/Should/Filter/MustNot, other queries go to must,
+// aggregations are groups that can be extended by SubGroup and metrics, sort is kept in order,
+// and others (size, from, _source ...) are kept as they are.
+// e.g ParseQuery(data).Table("esql").Term(F{"Level": 1}).Find(&result)
+func ParseQuery(data []byte) *Client {
+	c := DB("")
+	var body F
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		c.Error = fmt.Errorf("esql: parse query: %v", err)
+		return c
+	}
+	body = toF(body).(F)
+
+	if q, ok := body["query"]; ok {
+		c.parseQuery(q)
+	}
+	if q, ok := body["post_filter"]; ok {
+		c.parsePostFilter(q)
+	}
+	if s, ok := body["sort"]; ok {
+		if arr, ok := s.([]interface{}); ok {
+			c.sorts = arr
+		} else {
+			c.sorts = []interface{}{s}
+		}
+	}
+	for _, k := range []string{"aggs", "aggregations"} {
+		if aggs, ok := body[k].(F); ok {
+			c.groups.Append(aggs)
+		}
+	}
+
+	for k, v := range body {
+		switch k {
+		case "query", "post_filter", "sort", "aggs", "aggregations":
+		default:
+			c.search[k] = v
+		}
+	}
+	return c
+}
+
+func (c *Client) parseQuery(q interface{}) {
+	query, ok := q.(F)
+	if !ok {
+		c.Error = fmt.Errorf("esql: parse query: query is not an object")
+		return
+	}
+
+	// Dismax wraps the bool query in queries
+	if dismax, ok := query["dis_max"].(F); ok && len(query) == 1 {
+		if _bool, ok := dismax["queries"].(F); ok && len(_bool) == 1 && _bool["bool"] != nil {
+			c.dismax = F{}
+			for k, v := range dismax {
+				if k != "queries" {
+					c.dismax[k] = v
+				}
+			}
+			query = _bool
+		}
+	}
+
+	_bool, ok := query["bool"].(F)
+	if !ok || len(query) != 1 {
+		c.must = append(c.must, query)
+		return
+	}
+
+	for k, v := range _bool {
+		switch k {
+		case "must":
+			c.must = append(c.must, clauses(v)...)
+		case "should":
+			c.should = append(c.should, clauses(v)...)
+		case "filter":
+			c.filter = append(c.filter, clauses(v)...)
+		case "must_not":
+			for _, n := range clauses(v) {
+				c.mustnot = append(c.mustnot, Not(n))
+			}
+		default:
+			if c.boolOpts == nil {
+				c.boolOpts = F{}
+			}
+			c.boolOpts[k] = v
+		}
+	}
+}
+
+func (c *Client) parsePostFilter(q interface{}) {
+	query, ok := q.(F)
+	if !ok {
+		c.Error = fmt.Errorf("esql: parse query: post_filter is not an object")
+		return
+	}
+
+	if _bool, ok := query["bool"].(F); ok && len(query) == 1 {
+		only := true
+		for k := range _bool {
+			only = only && (k == "filter" || k == "must_not")
+		}
+		if only {
+			c.postFilter = append(c.postFilter, clauses(_bool["filter"])...)
+			for _, n := range clauses(_bool["must_not"]) {
+				c.postNot = append(c.postNot, Not(n))
+			}
+			return
+		}
+	}
+	c.postFilter = append(c.postFilter, query)
+}
+
+// clauses a clause or an array of clauses
+func clauses(i interface{}) (arr []F) {
+	switch v := i.(type) {
+	case F:
+		arr = append(arr, v)
+	case []interface{}:
+		for _, e := range v {
+			if f, ok := e.(F); ok {
+				arr = append(arr, f)
+			}
+		}
+	}
+	return
+}
+
+// toF converts the decoded json objects to F
+func toF(i interface{}) interface{} {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		f := F{}
+		for k, e := range v {
+			f[k] = toF(e)
+		}
+		return f
+	case F:
+		for k, e := range v {
+			v[k] = toF(e)
+		}
+		return v
+	case []interface{}:
+		for n, e := range v {
+			v[n] = toF(e)
+		}
+		return v
+	}
+	return i
+}
@@ -0,0 +1,67 @@
+package esql_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func sameJSON(a, b string) bool {
+	var x, y interface{}
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestParseQuery(t *testing.T) {
+	// round trip of the builders
+	built := []*esql.Client{
+		es.DB().Where(esql.F{"Name": "go"}).Or(esql.F{"Level": 1}).Not(esql.Not{"Number": 2}).
+			Bool(esql.F{"minimum_should_match": 1}).OrderBy("Level", esql.Desc).ThenBy("_score").Limit(10, 20).
+			Source([]string{"Name"}).Group("Level").Avg("Number", "group_Level"),
+		es.DB().Dismax(esql.F{"tie_breaker": 0.7}).Range(esql.F{"Level": esql.F{"gt": 1}}).
+			PostFilter("term", esql.F{"Number": 1}),
+		// Must wraps the clause in match, Clause adds the nested query as it is
+		es.DB().Clause("must", esql.Nested("As3", es.DB().Term(esql.F{"As3.Name": "go"}).Query())),
+	}
+	for _, v := range built {
+		want := v.Serialize().Template()
+		c := esql.ParseQuery([]byte(want))
+		if err := c.Serialize().Error; err != nil {
+			t.Fatal(err)
+		}
+		if !sameJSON(want, c.Template()) {
+			t.Fatal(want, c.Template())
+		}
+	}
+
+	// the other settings of bool query are kept
+	raw := `{"query":{"bool":{"minimum_should_match":1,"should":[{"match":{"Name":"go"}}]}}}`
+	if c := esql.ParseQuery([]byte(raw)).Serialize(); c.Error != nil || !sameJSON(raw, c.Template()) {
+		t.Fatal(c.Error, c.Template())
+	}
+
+	// raw query is extended by the builders
+	raw = `{
+		"query": {"match": {"Name": "go"}},
+		"sort": "Level",
+		"size": 12345678901234567,
+		"aggs": {"group_Level": {"terms": {"field": "Level"}}}
+	}`
+	c := esql.ParseQuery([]byte(raw)).Term(esql.F{"Number": 1}).Max("Number", "group_Level").Serialize()
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	want := `{"aggs":{"group_Level":{"aggs":{"metric_Number":{"max":{"field":"Number"}}},"terms":{"field":"Level"}}},` +
+		`"query":{"bool":{"filter":[{"term":{"Number":1}}],"must":[{"match":{"Name":"go"}}]}},"size":12345678901234567,"sort":["Level"]}`
+	if c.Template() != want {
+		t.Fatal(c.Template())
+	}
+
+	if esql.ParseQuery([]byte(`{"query": [1]}`)).Error == nil {
+		t.Fatal("query should be an object")
+	}
+}
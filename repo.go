@@ -0,0 +1,173 @@
+//go:build go1.18
+// +build go1.18
+
+package esql
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Repo the typed documents of index, T is the struct of document, see AutoMapping and Hit.Decode
+// e.g  blogs := NewRepo[blog]("blog"); b, err := blogs.Get("1")
+type Repo[T any] struct {
+	index string
+	// the batch size of Iterate, 500 by default
+	BatchSize int
+}
+
+// SearchMeta the metadata of search result of Repo
+type SearchMeta struct {
+	Took         int64
+	TimedOut     bool
+	Total        Total
+	MaxScore     float64
+	Aggregations map[string]json.RawMessage
+	ScrollID     string
+	// the metadata of hits, in order of the documents
+	Hits []Hit
+}
+
+// NewRepo a repository of T on index
+func NewRepo[T any](index string) *Repo[T] {
+	return &Repo[T]{index: index, BatchSize: 500}
+}
+
+// DB a new client on the index of repo
+func (r *Repo[T]) DB() *Client {
+	return DB(r.index)
+}
+
+// AutoMapping creates the index if it doesn't exist, and puts the mapping of T
+func (r *Repo[T]) AutoMapping() error {
+	var doc T
+	return r.DB().AutoMapping(doc).Error
+}
+
+// Get the document of id, ErrNotFound if it doesn't exist
+func (r *Repo[T]) Get(id string) (T, error) {
+	var doc T
+	var got struct {
+		Hit
+		Found bool `json:"found"`
+	}
+	c := r.DB().GetDocWithID(id)
+	if c.Response(&got); c.Error != nil {
+		return doc, c.Error
+	}
+	if !got.Found {
+		return doc, ErrNotFound
+	}
+	err := got.Hit.Decode(&doc)
+	return doc, err
+}
+
+// Index indexes the document, it's created or replaced by the field tagged esql:"_id", or a generated id.
+// the id of document is returned.
+func (r *Repo[T]) Index(doc T) (string, error) {
+	c := r.DB()
+	if id := metaID(doc); id != "" {
+		c.IndexDoc(id, doc)
+	} else {
+		c.AutoIndexDoc(doc)
+	}
+
+	var got struct {
+		ID string `json:"_id"`
+	}
+	if c.Response(&got); c.Error != nil {
+		return "", c.Error
+	}
+	return got.ID, nil
+}
+
+// Update merges patch into the document of id, patch is a partial document, e.g F{"Level": 2}
+func (r *Repo[T]) Update(id string, patch interface{}) error {
+	return r.DB().UpdatePartialDoc(id, F{"doc": patch}).Error
+}
+
+// Delete deletes the document of id
+func (r *Repo[T]) Delete(id string) error {
+	return r.DB().DeleteDoc(id).Error
+}
+
+// Search the documents of the client made by build, build can be nil to match all.
+// e.g docs, meta, err := blogs.Search(func(c *Client) { c.Where(F{"Title": "go"}).Limit(20) })
+func (r *Repo[T]) Search(build func(*Client)) ([]T, SearchMeta, error) {
+	c := r.DB()
+	if build != nil {
+		build(c)
+	}
+
+	var result SearchResult
+	if err := c.Find(&result).Error; err != nil {
+		return nil, SearchMeta{}, err
+	}
+	docs, err := decodeHits[T](result.Hits)
+	return docs, searchMeta(result), err
+}
+
+// Iterate calls fn on all documents of the client made by build in batches, it stops on the first error of fn.
+// build can be nil to iterate the whole index. see Client.ScrollAll
+func (r *Repo[T]) Iterate(build func(*Client), fn func(T) error) error {
+	c := r.DB()
+	if build != nil {
+		build(c)
+	}
+	size := r.BatchSize
+	if size <= 0 {
+		size = 500
+	}
+
+	return c.ScrollAll(size, "1m", func(hits []Hit) error {
+		docs, err := decodeHits[T](hits)
+		if err != nil {
+			return err
+		}
+		for _, doc := range docs {
+			if err := fn(doc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func decodeHits[T any](hits []Hit) ([]T, error) {
+	docs := make([]T, len(hits))
+	for i, h := range hits {
+		if err := h.Decode(&docs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+func searchMeta(r SearchResult) SearchMeta {
+	return SearchMeta{
+		Took:         r.Took,
+		TimedOut:     r.TimedOut,
+		Total:        r.Total,
+		MaxScore:     r.MaxScore,
+		Aggregations: r.Aggregations,
+		ScrollID:     r.ScrollID,
+		Hits:         r.Hits,
+	}
+}
+
+// metaID the value of string field tagged esql:"_id"
+func metaID(doc interface{}) string {
+	v := reflect.ValueOf(doc)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for n := 0; n < v.NumField(); n++ {
+		if v.Type().Field(n).Tag.Get("esql") == "_id" && v.Field(n).Kind() == reflect.String {
+			return v.Field(n).String()
+		}
+	}
+	return ""
+}
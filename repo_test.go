@@ -0,0 +1,75 @@
+//go:build go1.18
+// +build go1.18
+
+package esql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/han2015/esql"
+)
+
+type record struct {
+	ID     string `esql:"_id"`
+	Name   string
+	Number int `esql:"type:keyword"`
+	Level  int
+}
+
+func TestRepo(t *testing.T) {
+	repo := esql.NewRepo[record]("esql")
+	repo.BatchSize = 2
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+
+	id, err := repo.Index(record{ID: "repo_1", Name: "this is a test", Number: 1, Level: 1})
+	if err != nil || id != "repo_1" {
+		t.Fatal(id, err)
+	}
+	for _, v := range []record{{Name: "another one", Number: 1, Level: 2}, {Name: "the last", Number: 1, Level: 3}} {
+		if _, err := repo.Index(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Update("repo_1", esql.F{"Level": 4}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * time.Second)
+
+	doc, err := repo.Get("repo_1")
+	if err != nil || doc.ID != "repo_1" || doc.Level != 4 {
+		t.Fatal(doc, err)
+	}
+	// the metadata fields are not in _source
+	var raw struct {
+		Source map[string]interface{} `json:"_source"`
+	}
+	c := es.DB().GetDocWithID("repo_1")
+	if c.Response(&raw); c.Error != nil || raw.Source["ID"] != nil {
+		t.Fatal(c.Error, raw.Source)
+	}
+	if _, err := repo.Get("repo_404"); err != esql.ErrNotFound {
+		t.Fatal(err)
+	}
+
+	docs, meta, err := repo.Search(func(c *esql.Client) { c.Term(esql.F{"Number": 1}).OrderBy("Level") })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || meta.Total.Value != 3 || docs[2].ID != "repo_1" || meta.Hits[2].ID != "repo_1" {
+		t.Fatal(docs, meta)
+	}
+
+	n := 0
+	err = repo.Iterate(func(c *esql.Client) { c.Term(esql.F{"Number": 1}) }, func(r record) error {
+		n++
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+
+	if err := repo.Delete("repo_1"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,221 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// SearchResult the typed response of search api
+// e.g  var result esql.SearchResult; es.DB().Where(F{}).Find(&result)
+type SearchResult struct {
+	// milliseconds of the search
+	Took     int64
+	TimedOut bool
+	Shards   Shards
+	// the total hits, see Client.TrackTotalHits
+	Total    Total
+	MaxScore float64
+	Hits     []Hit
+	// the aggregations by name, decode them by Aggregation
+	Aggregations map[string]json.RawMessage
+	// see Client.Scroll
+	ScrollID string
+	// see Client.Profile
+	Profile *Profile
+}
+
+// Shards the shards which executed the search
+type Shards struct {
+	Total      int               `json:"total"`
+	Successful int               `json:"successful"`
+	Skipped    int               `json:"skipped"`
+	Failed     int               `json:"failed"`
+	Failures   []json.RawMessage `json:"failures"`
+}
+
+// Total the total hits, relation is "eq" when value is accurate, "gte" when it is a lower bound.
+// 6.x returns a number, 7.x returns {"value": 1, "relation": "eq"}
+type Total struct {
+	Value    int64  `json:"value"`
+	Relation string `json:"relation"`
+}
+
+// UnmarshalJSON accepts both the number and the object
+func (t *Total) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		type total Total
+		return json.Unmarshal(data, (*total)(t))
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	t.Relation = "eq"
+	return json.Unmarshal(data, &t.Value)
+}
+
+// Hit a document of search result
+type Hit struct {
+	Index  string          `json:"_index"`
+	Type   string          `json:"_type"`
+	ID     string          `json:"_id"`
+	Score  float64         `json:"_score"`
+	Source json.RawMessage `json:"_source"`
+	// see Client.Version and Client.SeqNoPrimaryTerm
+	Version     int64 `json:"_version"`
+	SeqNo       int64 `json:"_seq_no"`
+	PrimaryTerm int64 `json:"_primary_term"`
+	// see Client.StoredFields and Client.DocvalueFields
+	Fields map[string][]interface{} `json:"fields"`
+	// the names of clauses matched the document, see Client.Named
+	MatchedQueries []string `json:"matched_queries"`
+	// see Client.Explain
+	Explanation *Explanation `json:"_explanation"`
+	Shard       string       `json:"_shard"`
+	Node        string       `json:"_node"`
+	// the sort values of hit, see Client.OrderBy
+	Sort []interface{} `json:"sort"`
+	// the highlighted fragments by field
+	Highlight map[string][]string `json:"highlight"`
+	// the name of inner_hits, it is the path(nested) or type(has_child, has_parent) by default
+	InnerHits map[string]*SearchResult `json:"inner_hits"`
+}
+
+// UnmarshalJSON decodes the response of search, also the inner hits.
+func (r *SearchResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Took     int64  `json:"took"`
+		TimedOut bool   `json:"timed_out"`
+		Shards   Shards `json:"_shards"`
+		Hits     struct {
+			Total    Total    `json:"total"`
+			MaxScore *float64 `json:"max_score"`
+			Hits     []Hit    `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]json.RawMessage `json:"aggregations"`
+		ScrollID     string                     `json:"_scroll_id"`
+		Profile      *Profile                   `json:"profile"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = SearchResult{
+		Took:         raw.Took,
+		TimedOut:     raw.TimedOut,
+		Shards:       raw.Shards,
+		Total:        raw.Hits.Total,
+		Hits:         raw.Hits.Hits,
+		Aggregations: raw.Aggregations,
+		ScrollID:     raw.ScrollID,
+		Profile:      raw.Profile,
+	}
+	if raw.Hits.MaxScore != nil {
+		r.MaxScore = *raw.Hits.MaxScore
+	}
+	return nil
+}
+
+// Aggregation decodes the aggregation of name into i, it returns false if the aggregation is not found.
+// e.g var g struct{ Buckets []struct{ Key string; DocCount int64 `json:"doc_count"` } }
+// result.Aggregation("group_color", &g)
+func (r *SearchResult) Aggregation(name string, i interface{}) (bool, error) {
+	data, ok := r.Aggregations[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, i)
+}
+
+// Decode decodes the _source of hit into i, the fields of struct tagged with the metadata of hit are set,
+// they are esql:"_id", esql:"_index", esql:"_type", esql:"_score", esql:"_version", esql:"_seq_no"
+// and esql:"_primary_term"
+// the hit without _source (e.g Source(false), StoredFields) sets the metadata only.
+// e.g  type blog struct { ID string `esql:"_id" json:"-"`; Title string }
+func (h Hit) Decode(i interface{}) error {
+	if len(h.Source) > 0 {
+		if err := json.Unmarshal(h.Source, i); err != nil {
+			return err
+		}
+	}
+
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	for n := 0; n < v.NumField(); n++ {
+		meta, ok := metaFields[v.Type().Field(n).Tag.Get("esql")]
+		if !ok || !v.Field(n).CanSet() {
+			continue
+		}
+		value, field := reflect.ValueOf(meta(h)), v.Field(n)
+		// numbers are not converted to strings
+		if (value.Kind() == reflect.String) == (field.Kind() == reflect.String) && value.Type().ConvertibleTo(field.Type()) {
+			field.Set(value.Convert(field.Type()))
+		}
+	}
+	return nil
+}
+
+// the metadata of hit by tag
+var metaFields = map[string]func(h Hit) interface{}{
+	"_id":           func(h Hit) interface{} { return h.ID },
+	"_index":        func(h Hit) interface{} { return h.Index },
+	"_type":         func(h Hit) interface{} { return h.Type },
+	"_score":        func(h Hit) interface{} { return h.Score },
+	"_version":      func(h Hit) interface{} { return h.Version },
+	"_seq_no":       func(h Hit) interface{} { return h.SeqNo },
+	"_primary_term": func(h Hit) interface{} { return h.PrimaryTerm },
+}
+
+// Scan decodes the hits into i, i is a pointer to slice of struct or *struct, see Hit.Decode
+// e.g  var blogs []blog; result.Scan(&blogs)
+func (r *SearchResult) Scan(i interface{}) error {
+	t := reflect.TypeOf(i)
+	if !isStructSlice(t) {
+		return fmt.Errorf("esql: Scan needs a pointer to slice of struct, but got %T", i)
+	}
+
+	slice := reflect.ValueOf(i).Elem()
+	slice.Set(reflect.MakeSlice(t.Elem(), 0, len(r.Hits)))
+	for _, h := range r.Hits {
+		if err := appendHit(slice, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendHit decodes the hit into a new element of slice
+func appendHit(slice reflect.Value, h Hit) error {
+	elem := slice.Type().Elem()
+	var e reflect.Value
+	if elem.Kind() == reflect.Ptr {
+		e = reflect.New(elem.Elem())
+	} else {
+		e = reflect.New(elem)
+	}
+	if err := h.Decode(e.Interface()); err != nil {
+		return err
+	}
+	if elem.Kind() != reflect.Ptr {
+		e = e.Elem()
+	}
+	slice.Set(reflect.Append(slice, e))
+	return nil
+}
+
+// *[]struct or *[]*struct
+func isStructSlice(t reflect.Type) bool {
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
+}
@@ -0,0 +1,111 @@
+package esql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestSearchResult(t *testing.T) {
+	// 6.x
+	data := `{"took":3,"timed_out":false,"_scroll_id":"abc","_shards":{"total":1,"successful":1,"skipped":0,"failed":0},
+		"hits":{"total":2,"max_score":null,"hits":[
+			{"_index":"esql","_type":"_doc","_id":"1","_score":null,"_source":{"Name":"go"},"sort":[1,"go"],
+			 "highlight":{"Name":["<em>go</em>"]}}]},
+		"aggregations":{"group_Level":{"buckets":[{"key":1,"doc_count":2}]}}}`
+	var result esql.SearchResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Took != 3 || result.Shards.Successful != 1 || result.Total != (esql.Total{Value: 2, Relation: "eq"}) ||
+		result.ScrollID != "abc" || result.MaxScore != 0 {
+		t.Fatal(result)
+	}
+	hit := result.Hits[0]
+	if hit.ID != "1" || len(hit.Sort) != 2 || hit.Highlight["Name"][0] != "<em>go</em>" || string(hit.Source) != `{"Name":"go"}` {
+		t.Fatal(hit)
+	}
+
+	var group struct {
+		Buckets []struct {
+			Key      int
+			DocCount int64 `json:"doc_count"`
+		}
+	}
+	if ok, err := result.Aggregation("group_Level", &group); !ok || err != nil || group.Buckets[0].DocCount != 2 {
+		t.Fatal(ok, err, group)
+	}
+	if ok, _ := result.Aggregation("group_Name", &group); ok {
+		t.Fatal("group_Name is not existed")
+	}
+
+	// 7.x
+	data = `{"took":1,"timed_out":true,"hits":{"total":{"value":10000,"relation":"gte"},"max_score":1.5,"hits":[]}}`
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+	if !result.TimedOut || result.Total != (esql.Total{Value: 10000, Relation: "gte"}) || result.MaxScore != 1.5 ||
+		len(result.Hits) != 0 || result.ScrollID != "" {
+		t.Fatal(result)
+	}
+}
+
+type blog struct {
+	ID      string  `esql:"_id" json:"-"`
+	Score   float32 `esql:"_score" json:"-"`
+	Version int     `esql:"_version" json:"-"`
+	Index   int     `esql:"_index" json:"-"`
+	Name    string
+}
+
+func TestScan(t *testing.T) {
+	data := `{"hits":{"hits":[{"_index":"esql","_id":"1","_score":1.5,"_version":2,"_source":{"Name":"go"}},` +
+		`{"_index":"esql","_id":"2","_source":{"Name":"rust"}}]}}`
+	var result esql.SearchResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	var blogs []blog
+	if err := result.Scan(&blogs); err != nil {
+		t.Fatal(err)
+	}
+	if len(blogs) != 2 || blogs[0] != (blog{ID: "1", Score: 1.5, Version: 2, Name: "go"}) || blogs[1].ID != "2" {
+		t.Fatal(blogs)
+	}
+
+	var ptrs []*blog
+	if err := result.Scan(&ptrs); err != nil || len(ptrs) != 2 || ptrs[1].Name != "rust" {
+		t.Fatal(err, ptrs)
+	}
+	if err := result.Scan(&[]string{}); err == nil {
+		t.Fatal("Scan needs a slice of struct")
+	}
+
+	// the hits without _source
+	data = `{"hits":{"hits":[{"_index":"esql","_id":"3","_score":1}]}}`
+	result = esql.SearchResult{}
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatal(err)
+	}
+	if err := result.Scan(&blogs); err != nil || len(blogs) != 1 || blogs[0] != (blog{ID: "3", Score: 1}) {
+		t.Fatal(err, blogs)
+	}
+}
+
+func TestFindSlice(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(mysql{Name: "this is a test", Level: 1, Number: 1}, mysql{Name: "another one", Level: 2, Number: 1})
+
+	var records []struct {
+		ID string `esql:"_id"`
+		mysql
+	}
+	if err := es.DB().Term(esql.F{"Number": 1}).OrderBy("Level").Find(&records).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].ID == "" || records[1].Name != "another one" {
+		t.Fatal(records)
+	}
+}
@@ -0,0 +1,120 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// Param a placeholder of stored search template, it is the value {{#toJson}}name{{/toJson}} in template.
+// e.g es.DB().Term(F{"Level": Param("level")}).StoreTemplate("by_level")
+type Param string
+
+// MarshalJSON the placeholder is quoted in json, StoreTemplate unquotes it
+func (p Param) MarshalJSON() ([]byte, error) {
+	return json.Marshal(p.placeholder())
+}
+
+func (p Param) placeholder() string {
+	return "{{#toJson}}" + string(p) + "{{/toJson}}"
+}
+
+var quotedParam = regexp.MustCompile(`"(\{\{#toJson\}\}[^"{}]*\{\{/toJson\}\})"`)
+
+// PutScript stores script in cluster, script is a painless script or a mustache template
+// F{"lang": "painless", "source": "Math.log(_score * 2) + params.my_modifier"}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/modules-scripting-using.html#modules-scripting-stored-scripts
+func (c *Client) PutScript(id string, script F) *Client {
+	data, _ := json.Marshal(F{"script": script})
+	c.method = "POST"
+	c.hostDB.Path = path.Join("_scripts", id)
+	c.template = string(data)
+	return c.exec(c.hostDB.String(), c.template)
+}
+
+// GetScript gets the stored script or template, read it by Response
+// {"_id": "id", "found": true, "script": {"lang": "mustache", "source": "..."}}
+func (c *Client) GetScript(id string) *Client {
+	c.method = "GET"
+	c.hostDB.Path = path.Join("_scripts", id)
+	return c.exec(c.hostDB.String())
+}
+
+// DeleteScript deletes the stored script or template
+func (c *Client) DeleteScript(id string) *Client {
+	c.method = "DELETE"
+	c.hostDB.Path = path.Join("_scripts", id)
+	return c.exec(c.hostDB.String())
+}
+
+// PutTemplate stores a mustache search template, source is F or string
+// F{"query": F{"match": F{"Name": "{{name}}"}}}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-template.html#pre-registered-templates
+func (c *Client) PutTemplate(id string, source interface{}) *Client {
+	return c.PutScript(id, F{"lang": "mustache", "source": source})
+}
+
+// StoreTemplate stores the serialized settings of client as a search template, the Param values are
+// the placeholders of template.
+// e.g es.DB().Where(F{"Name": Param("name")}).Limit(10).StoreTemplate("by_name")
+// es.DB().SearchTemplate("by_name", F{"name": "go"}).Response(&result)
+func (c *Client) StoreTemplate(id string) *Client {
+	if c.Serialize().Error != nil {
+		return c
+	}
+	return c.PutTemplate(id, quotedParam.ReplaceAllString(c.template, "$1"))
+}
+
+// SearchTemplate searchs by the stored template with params, read the hits by Response
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-template.html
+func (c *Client) SearchTemplate(id string, params F) *Client {
+	return c.searchTemplate(F{"id": id, "params": params})
+}
+
+// SearchInline searchs by the inline template with params, source is F or string
+// F{"query": F{"match": F{"Name": "{{name}}"}}, "size": "{{size}}"}
+func (c *Client) SearchInline(source interface{}, params F) *Client {
+	return c.searchTemplate(F{"source": source, "params": params})
+}
+
+func (c *Client) searchTemplate(body F) *Client {
+	data, _ := json.Marshal(body)
+	c.method = "POST"
+	c.hostDB.Path = path.Join(c.hostDB.Path, "_search/template")
+	c.template = string(data)
+	return c.exec(c.uri(), c.template)
+}
+
+// RenderTemplate renders the stored template with params to the search body, for debugging
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-template.html#_validating_templates
+func (c *Client) RenderTemplate(id string, params F) (string, error) {
+	return c.render(F{"id": id, "params": params})
+}
+
+// RenderInline renders the inline template with params to the search body
+func (c *Client) RenderInline(source interface{}, params F) (string, error) {
+	return c.render(F{"source": source, "params": params})
+}
+
+func (c *Client) render(body F) (string, error) {
+	data, _ := json.Marshal(body)
+	c.method = "POST"
+	c.hostDB.Path = "_render/template"
+	if c.exec(c.hostDB.String(), string(data)).Error != nil {
+		return "", c.Error
+	}
+
+	var got struct {
+		Output json.RawMessage `json:"template_output"`
+	}
+	if c.Response(&got); c.Error != nil {
+		return "", c.Error
+	}
+	if got.Output == nil {
+		c.Error = fmt.Errorf("esql: no template_output in %s", c.response)
+		return "", c.Error
+	}
+	c.template = string(got.Output)
+	return c.template, nil
+}
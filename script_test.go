@@ -0,0 +1,66 @@
+package esql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestSearchTemplate(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(mysql{Name: "this is a test", Level: 1, Number: 1}, mysql{Name: "another one", Level: 2, Number: 1})
+
+	c := es.DB().Where(esql.F{"Name": esql.Param("name")}).Term(esql.F{"Number": esql.Param("number")}).
+		StoreTemplate("esql_by_name")
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	t.Log(c.Template())
+
+	var script struct {
+		Found  bool
+		Script struct {
+			Lang   string
+			Source string
+		}
+	}
+	if es.DB().GetScript("esql_by_name").Response(&script); !script.Found || script.Script.Lang != "mustache" {
+		t.Fatal(script)
+	}
+	want := `{"query":{"bool":{"filter":[{"term":{"Number":{{#toJson}}number{{/toJson}}}}],"must":[{"match":{"Name":{{#toJson}}name{{/toJson}}}}]}}}`
+	if script.Script.Source != want {
+		t.Fatal(script.Script.Source)
+	}
+
+	body, err := es.DB().RenderTemplate("esql_by_name", esql.F{"name": "another", "number": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rendered esql.F
+	if err := json.Unmarshal([]byte(body), &rendered); err != nil {
+		t.Fatal(body, err)
+	}
+
+	var result esql.SearchResult
+	c = es.DB().SearchTemplate("esql_by_name", esql.F{"name": "another", "number": 1})
+	if c.Response(&result); c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	if len(result.Hits) != 1 {
+		t.Fatal(result.Hits)
+	}
+
+	inline := esql.F{"query": esql.F{"match": esql.F{"Name": "{{name}}"}}, "size": "{{size}}"}
+	c = es.DB().SearchInline(inline, esql.F{"name": "test", "size": 1})
+	if c.Response(&result); c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	if len(result.Hits) != 1 {
+		t.Fatal(result.Hits)
+	}
+
+	if err := es.DB().DeleteScript("esql_by_name").Error; err != nil {
+		t.Fatal(err)
+	}
+}
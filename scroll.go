@@ -0,0 +1,108 @@
+package esql
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+)
+
+// ScrollAll scrolls all hits of the query in batches of size, fn is called on every batch till the hits are
+// exhausted, fn returns error to stop. the scroll is cleared at the end, also on error or the cancellation
+// of context, see WithContext.
+// keepAlive: the time to keep the search context between batches, 1m by default.
+// e.g es.DB().Term(F{"Level": 1}).ScrollAll(500, "1m", func(hits []Hit) error { ... })
+func (c *Client) ScrollAll(size int, keepAlive string, fn func(hits []Hit) error) error {
+	return c.scroll(size, keepAlive, func(result *SearchResult) error {
+		return fn(result.Hits)
+	})
+}
+
+// scroll calls fn on every page of scroll
+func (c *Client) scroll(size int, keepAlive string, fn func(result *SearchResult) error) error {
+	if keepAlive == "" {
+		keepAlive = "1m"
+	}
+
+	var result SearchResult
+	if c.Scroll(size, keepAlive).Find(&result).Error != nil {
+		return c.Error
+	}
+	scrollID := result.ScrollID
+	defer func() {
+		if scrollID != "" {
+			// the context may be canceled, the scroll is still cleared
+			DB("").ClearScroll(scrollID)
+		}
+	}()
+
+	for len(result.Hits) > 0 {
+		if c.ctx != nil && c.ctx.Err() != nil {
+			return c.ctx.Err()
+		}
+		if err := fn(&result); err != nil {
+			return err
+		}
+
+		if c.GetScroll(scrollID, keepAlive).Error != nil {
+			return c.Error
+		}
+		result = SearchResult{}
+		if c.Response(&result); c.Error != nil {
+			return c.Error
+		}
+		if result.ScrollID != "" {
+			scrollID = result.ScrollID
+		}
+	}
+	return nil
+}
+
+// ScrollChan the channel variant of ScrollAll, the hits are sent one by one, the channel of error receives
+// the error of scroll or nil after the hits are closed.
+// call stop to quit before the hits are exhausted, or the scroll is not cleared till the hits are drained,
+// the cancellation of context of client also stops it, see WithContext.
+// e.g  hits, errs, stop := es.DB().Term(F{"Level": 1}).ScrollChan(500, "1m")
+// defer stop()
+// for h := range hits { ... }
+// err := <-errs
+func (c *Client) ScrollChan(size int, keepAlive string) (hits <-chan Hit, errs <-chan error, stop func()) {
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	c.ctx = ctx
+
+	out, result := make(chan Hit), make(chan error, 1)
+	go func() {
+		defer cancel()
+		defer close(result)
+		defer close(out)
+		result <- c.ScrollAll(size, keepAlive, func(batch []Hit) error {
+			for _, h := range batch {
+				select {
+				case out <- h:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}()
+	return out, result, cancel
+}
+
+// ClearScroll clears the search contexts of scroll ids, all of them if no id is given
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-scroll.html#_clear_scroll_api
+func (c *Client) ClearScroll(scrollID ...string) *Client {
+	c.method = "DELETE"
+	c.hostDB.Path = "_search/scroll"
+	if len(scrollID) == 0 {
+		c.hostDB.Path = path.Join(c.hostDB.Path, "_all")
+		return c.exec(c.hostDB.String())
+	}
+
+	data, _ := json.Marshal(F{"scroll_id": scrollID})
+	c.template = string(data)
+	return c.exec(c.hostDB.String(), c.template)
+}
@@ -0,0 +1,65 @@
+package esql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestScrollAll(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "another one", Level: 2, Number: 1},
+		mysql{Name: "the last", Level: 3, Number: 1},
+	)
+
+	n, batches := 0, 0
+	err := es.DB().Term(esql.F{"Number": 1}).ScrollAll(2, "1m", func(hits []esql.Hit) error {
+		n, batches = n+len(hits), batches+1
+		return nil
+	})
+	if err != nil || n != 3 || batches != 2 {
+		t.Fatal(n, batches, err)
+	}
+
+	stop := errors.New("stop")
+	err = es.DB().Term(esql.F{"Number": 1}).ScrollAll(2, "1m", func(hits []esql.Hit) error { return stop })
+	if err != stop {
+		t.Fatal(err)
+	}
+
+	hits, errs, quit := es.DB().Term(esql.F{"Number": 1}).ScrollChan(2, "1m")
+	defer quit()
+	n = 0
+	for range hits {
+		n++
+	}
+	if err := <-errs; err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hits, errs, _ = es.DB().WithContext(ctx).Term(esql.F{"Number": 1}).ScrollChan(1, "1m")
+	<-hits
+	cancel()
+	for range hits {
+	}
+	if err := <-errs; err == nil {
+		t.Fatal("scroll should be canceled")
+	}
+
+	// stop without draining the hits
+	hits, errs, quit = es.DB().Term(esql.F{"Number": 1}).ScrollChan(1, "1m")
+	<-hits
+	quit()
+	if err := <-errs; err == nil {
+		t.Fatal("scroll should be stopped")
+	}
+
+	if err := es.DB().ClearScroll().Error; err != nil {
+		t.Fatal(err)
+	}
+}
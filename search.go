@@ -6,18 +6,24 @@ import (
 	"fmt"
 	"path"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
 // Find  makes search querry, then start query, and scan the result to i.
 // i must be the reflect.Ptr.  e.g &F, &struct{}
+// if i is a pointer to slice of struct or *struct, the _source of hits are decoded into it one by one from the
+// response stream, see FindEach and Hit.Decode.
 // this should be the last chain when you do any search.
 // es.DB().Where(F{}).Match(F{}).Not(F{}).Or(F{}).Between(F{}).In(F{}).Range(F{}).Term(F{}).Order(F{}).Limit(5).Find(&Response{})
 func (c *Client) Find(i interface{}) *Client {
+	if isStructSlice(reflect.TypeOf(i)) {
+		return c.findSlice(i)
+	}
 	c.Serialize()
 	c.hostDB.Path = path.Join(c.hostDB.Path, "_search")
 	if i == nil {
-		return c.exec(c.hostDB.String(), c.template)
+		return c.exec(c.uri(), c.template)
 	}
 
 	t := reflect.TypeOf(i)
@@ -26,7 +32,7 @@ func (c *Client) Find(i interface{}) *Client {
 		return c
 	}
 
-	if err := c.exec(c.hostDB.String(), c.template).Error; err != nil {
+	if err := c.exec(c.uri(), c.template).Error; err != nil {
 		return c
 	}
 
@@ -34,6 +40,58 @@ func (c *Client) Find(i interface{}) *Client {
 	return c
 }
 
+// CountDocs counts the documents matching the query, more efficient than a search.
+// es.DB().Where(F{}).Routing("user1").TerminateAfter(100).CountDocs()
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-count.html
+func (c *Client) CountDocs() (int64, error) {
+	if c.Error != nil {
+		return 0, c.Error
+	}
+
+	_search := F{}
+	if query := c.searchQuery(); query != nil {
+		_search["query"] = query
+	}
+	data, err := json.Marshal(_search)
+	if err == nil && c.strict {
+		err = c.validate(data)
+	}
+	c.template, c.Error = string(data), err
+	c.clear()
+	c.hostDB.Path = path.Join(c.hostDB.Path, "_count")
+	if err := c.exec(c.uri("routing", "preference", "terminate_after"), c.template).Error; err != nil {
+		return 0, err
+	}
+
+	var got struct {
+		Count int64 `json:"count"`
+	}
+	c.Error = json.Unmarshal(c.response, &got)
+	return got.Count, c.Error
+}
+
+// Routing executes the request only on the shards of routing values
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search.html#search-routing
+func (c *Client) Routing(routing ...string) *Client {
+	c.queries.Set("routing", strings.Join(routing, ","))
+	return c
+}
+
+// Preference controls which shard copies to execute the request on, e.g "_local" or a custom string
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-preference.html
+func (c *Client) Preference(preference string) *Client {
+	c.queries.Set("preference", preference)
+	return c
+}
+
+// TerminateAfter the maximum number of documents to collect for each shard, upon reaching which
+// the query execution will terminate early.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-body.html
+func (c *Client) TerminateAfter(n int) *Client {
+	c.queries.Set("terminate_after", strconv.Itoa(n))
+	return c
+}
+
 //Dismax F{"tie_breaker" : 1, "boost" : 1}
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-dis-max-query.html
 func (c *Client) Dismax(i Setting) *Client {
@@ -49,6 +107,17 @@ func (c *Client) Bool(i Setting) *Client {
 	return c
 }
 
+// Named gives a name to the clauses made by clauses, the names of clauses which
+// matched a document are returned in matched_queries of the hit. see Hit.MatchedQueries
+// e.g Named("title", func(c *Client) { c.Where(F{"Name": "go"}) }).Range(F{"Level": F{"gt": 1}})
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-named-queries-and-filters.html
+func (c *Client) Named(name string, clauses func(c *Client)) *Client {
+	c.name = name
+	clauses(c)
+	c.name = ""
+	return c
+}
+
 // func (c *Client) parseParams(i ...string) *Client {
 // 	for _, v := range i {
 // 		c.mustnot = append(c.mustnot, F{"exists": v})
@@ -87,7 +156,7 @@ func (c *Client) In(i ...Setting) *Client {
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-exists-query.html#_literal_missing_literal_query
 func (c *Client) Missing(i ...string) *Client {
 	for _, v := range i {
-		c.mustnot = append(c.mustnot, Not{"exists": F{"field": v}})
+		c.mustnot = append(c.mustnot, Not(c.named("exists", F{"field": v})))
 	}
 	return c
 }
@@ -97,7 +166,7 @@ func (c *Client) Missing(i ...string) *Client {
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-exists-query.html#query-dsl-exists-query
 func (c *Client) NotNil(i ...string) *Client {
 	for _, v := range i {
-		c.filter = append(c.filter, F{"exists": F{"field": v}})
+		c.filter = append(c.filter, c.named("exists", F{"field": v}))
 	}
 	return c
 }
@@ -118,12 +187,13 @@ func (c *Client) Between(i ...Setting) *Client {
 // 	{ "age" : "desc" },
 // 	"_score"
 // ]
+// the fields in one F are sorted by name, to keep your order, put them in different F or use OrderBy.
 func (c *Client) Order(i ...F) *Client {
 	arr := []interface{}{}
 	for _, v := range i {
 		arr = append(arr, v.Fields()...)
 	}
-	c.search["sort"] = arr
+	c.sorts = arr
 	return c
 }
 
@@ -140,6 +210,69 @@ func (c *Client) Limit(n ...int) *Client {
 	return c
 }
 
+// TrackTotalHits true counts the total hits accurately, false skips counting, a number counts accurately up to it.
+// https://www.elastic.co/guide/en/elasticsearch/reference/7.x/search-request-body.html#request-body-search-track-total-hits
+func (c *Client) TrackTotalHits(i interface{}) *Client {
+	c.search["track_total_hits"] = i
+	return c
+}
+
+// MinScore excludes documents which have a _score less than the minimum
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-min-score.html
+func (c *Client) MinScore(score float64) *Client {
+	c.search["min_score"] = score
+	return c
+}
+
+// SearchTimeout the search timeout in request body, bounding the search request to be executed within it.
+// different with Timeout which is put in the path of any request.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-body.html
+func (c *Client) SearchTimeout(out string) *Client {
+	c.search["timeout"] = out
+	return c
+}
+
+// SearchType query_then_fetch(default) or dfs_query_then_fetch, put in the path
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-search-type.html
+func (c *Client) SearchType(types string) *Client {
+	c.queries.Set("search_type", types)
+	return c
+}
+
+// StoredFields loads the stored fields of document, the values are returned in Hit.Fields
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-stored-fields.html
+func (c *Client) StoredFields(fields ...string) *Client {
+	c.search["stored_fields"] = fields
+	return c
+}
+
+// DocvalueFields returns the doc value of field, format is optional, e.g "epoch_millis" for date.
+// call it many times for many fields
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-docvalue-fields.html
+func (c *Client) DocvalueFields(field string, format ...string) *Client {
+	var _set interface{} = field
+	if len(format) > 0 {
+		_set = F{"field": field, "format": format[0]}
+	}
+	arr, _ := c.search["docvalue_fields"].([]interface{})
+	c.search["docvalue_fields"] = append(arr, _set)
+	return c
+}
+
+// Version returns the version of every hit
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-version.html
+func (c *Client) Version(on bool) *Client {
+	c.search["version"] = on
+	return c
+}
+
+// SeqNoPrimaryTerm returns the sequence number and primary term of the last modification of every hit
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.8/search-request-seq-no-primary-term.html
+func (c *Client) SeqNoPrimaryTerm(on bool) *Client {
+	c.search["seq_no_primary_term"] = on
+	return c
+}
+
 //StringQuery Perform the query on all fields detected in the mapping that can be queried. Will be used by default when the _all field is disabled and no default_field is specified (either in the index settings or in the request body) and no fields are specified.
 // {"query_string" : { "query": "value string", "fields" : ["name1", "name2"], "other" : interface}}
 // F{ "query": "value string", "fields" : ["name1", "name2"], "other" : interface}
@@ -192,6 +325,44 @@ func (c *Client) Filter(i ...F) *Client {
 	return c
 }
 
+// PostFilter filters the hits after aggregations are calculated, the aggregations are not affected.
+// types: term, terms, range, match ... ; the Not settings are put into must_not
+// e.g  PostFilter("term", F{"color": "red"}, Not{"brand": "gucci"})
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-post-filter.html
+func (c *Client) PostFilter(types string, i ...Setting) *Client {
+	arr, nots := c.divide(types, i)
+	c.postFilter = append(c.postFilter, arr...)
+	c.postNot = append(c.postNot, nots...)
+	return c
+}
+
+// Clause puts prepared queries into a bool clause as they are, without any wrapping.
+// occur: must, should, filter, must_not
+// e.g Clause("should", SpanFirst(SpanTerm("Name", "this"), 1))
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-bool-query.html#query-dsl-bool-query
+func (c *Client) Clause(occur string, q ...F) *Client {
+	arr := make([]F, 0, len(q))
+	for _, v := range q {
+		arr = append(arr, c.nameQuery(v))
+	}
+
+	switch occur {
+	case "must":
+		c.must = append(c.must, arr...)
+	case "should":
+		c.should = append(c.should, arr...)
+	case "filter":
+		c.filter = append(c.filter, arr...)
+	case "must_not":
+		for _, v := range arr {
+			c.mustnot = append(c.mustnot, Not(v))
+		}
+	default:
+		c.Error = fmt.Errorf("esql: unknown bool clause %s", occur)
+	}
+	return c
+}
+
 //Match  {"match" : {"field" : interface}}
 // F{"field" : interface}
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-match-query.html
@@ -265,7 +436,7 @@ func (c *Client) Wildcard(i ...Setting) *Client {
 func (c *Client) GeoBox(fieldName string, top, left, bottom, right float64) *Client {
 	geo := F{}
 	geo[fieldName] = F{"top": top, "left": left, "bottom": bottom, "right": right}
-	c.filter = append(c.filter, F{"geo_bounding_box": geo})
+	c.filter = append(c.filter, c.named("geo_bounding_box", geo))
 	return c
 }
 
@@ -275,7 +446,7 @@ func (c *Client) GeoBox(fieldName string, top, left, bottom, right float64) *Cli
 func (c *Client) GeoDistance(fieldName, dis string, lat, lon float64) *Client {
 	geo := F{}
 	geo["geo_distance"] = F{"distance": dis, fieldName: F{"lat": lat, "lon": lon}}
-	c.filter = append(c.filter, geo)
+	c.filter = append(c.filter, c.nameQuery(geo))
 	return c
 }
 
@@ -302,6 +473,8 @@ func (c *Client) GetScroll(scrollID, expires string) *Client {
 
 //Joins elasticsearch-join different with sql's action
 // 'i' is a setting of Joins, should only one, don't use it as conditions
+// it wraps the whole bool query, to combine joins with other conditions, use Nested, HasChild,
+// HasParent or ParentID with Clause instead.
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-nested-query.html
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-has-child-query.html
 // https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-has-parent-query.html
@@ -357,6 +530,13 @@ func (c *Client) ValidateQuery() *Client {
 
 // divide Setting into correct query
 func (c *Client) reflect(types string, i interface{}) (arr []F) {
+	arr, nots := c.divide(types, i)
+	c.mustnot = append(c.mustnot, nots...)
+	return
+}
+
+// divide makes queries of settings, the Not settings are returned as nots
+func (c *Client) divide(types string, i interface{}) (arr []F, nots []Not) {
 	tt := reflect.ValueOf(i)
 	l := tt.Len()
 	switch reflect.TypeOf(i).Elem().Name() {
@@ -364,22 +544,91 @@ func (c *Client) reflect(types string, i interface{}) (arr []F) {
 		cons := i.([]Setting)
 		for n := 0; n < l; n++ {
 			if tt.Index(n).Elem().Type().Name() == "Not" {
-				c.mustnot = append(c.mustnot, Not{types: cons[n]})
+				nots = append(nots, Not(c.named(types, cons[n])))
 				continue
 			}
-			arr = append(arr, F{types: cons[n]})
+			arr = append(arr, c.named(types, cons[n]))
 		}
 	case "F":
 		fs := i.([]F)
 		for n := 0; n < l; n++ {
-			arr = append(arr, F{types: fs[n]})
+			arr = append(arr, c.named(types, fs[n]))
 		}
 	case "Not":
 		fs := i.([]Not)
 		for n := 0; n < l; n++ {
-			c.mustnot = append(c.mustnot, Not{types: fs[n]})
+			nots = append(nots, Not(c.named(types, fs[n])))
 		}
 	}
 
 	return
 }
+
+// the value key of queries which are set on fields, {"term" : {"field" : {"value" : interface, "_name" : name}}}
+var namedValueKeys = map[string]string{
+	"match":               "query",
+	"match_phrase":        "query",
+	"match_phrase_prefix": "query",
+	"term":                "value",
+	"prefix":              "value",
+	"wildcard":            "value",
+	"regexp":              "value",
+	"fuzzy":               "value",
+	"span_term":           "value",
+	"range":               "",
+}
+
+// named makes the query {types: setting}, and gives it the name of Named if it was set.
+// the setting is copied, so the settings of caller will not be changed.
+func (c *Client) named(types string, setting interface{}) F {
+	if c.name == "" {
+		return F{types: setting}
+	}
+
+	body, ok := setting.(map[string]interface{})
+	if !ok {
+		switch v := setting.(type) {
+		case F:
+			body = v
+		case Not:
+			body = v
+		default:
+			return F{types: setting}
+		}
+	}
+
+	_set := F{}
+	key, onField := namedValueKeys[types]
+	for k, v := range body {
+		if !onField || k == "boost" || k == "_name" {
+			_set[k] = v
+			continue
+		}
+		field := F{}
+		switch fv := v.(type) {
+		case F:
+			field.Append(fv)
+		case map[string]interface{}:
+			field.Append(fv)
+		default:
+			field[key] = v
+		}
+		field["_name"] = c.name
+		_set[k] = field
+	}
+	if !onField {
+		_set["_name"] = c.name
+	}
+	return F{types: _set}
+}
+
+// nameQuery gives the prepared query the name of Named
+func (c *Client) nameQuery(q F) F {
+	if c.name == "" {
+		return q
+	}
+	for k, v := range q {
+		q = c.named(k, v)
+	}
+	return q
+}
@@ -1,6 +1,7 @@
 package esql_test
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -525,3 +526,125 @@ func TestWildcard(t *testing.T) {
 		}
 	}
 }
+
+func TestNamed(t *testing.T) {
+	// the name is not on the clauses made out of Named
+	c := es.DB().Named("a", func(c *esql.Client) { c.Limit(5).Term(esql.F{"Level": 1}) }).Term(esql.F{"Number": 1})
+	want := `{"query":{"bool":{"filter":[{"term":{"Level":{"_name":"a","value":1}}},{"term":{"Number":1}}]}},"size":5}`
+	if c.Serialize().Template() != want {
+		t.Fatal(c.Template())
+	}
+
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "another one", Level: 5, Number: 1},
+	)
+
+	var result esql.SearchResult
+	c = es.DB().Term(esql.F{"Number": 1}).
+		Named("name", func(c *esql.Client) { c.Or(esql.F{"Name": "test"}) }).
+		Named("level", func(c *esql.Client) { c.Should(esql.F{"Level": 5}) }).
+		Find(&result)
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	t.Log(c.Template())
+
+	if len(result.Hits) != 2 {
+		t.Fatal(len(result.Hits))
+	}
+	for _, v := range result.Hits {
+		var doc mysql
+		v.Decode(&doc)
+		if len(v.MatchedQueries) != 1 {
+			t.Fatal(v.MatchedQueries)
+		}
+		if (doc.Level == 5) != (v.MatchedQueries[0] == "level") {
+			t.Fatal(doc, v.MatchedQueries)
+		}
+	}
+}
+
+func TestCountDocs(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "this is a test", Level: 2, Number: 1},
+		mysql{Name: "another one", Level: 3, Number: 1},
+	)
+
+	cases := []struct {
+		client *esql.Client
+		want   int64
+	}{
+		{client: es.DB().Term(esql.F{"Number": 1}), want: 3},
+		{client: es.DB().Term(esql.F{"Number": 1}).Where(esql.F{"Name": "test"}), want: 2},
+		{client: es.DB().Term(esql.F{"Number": 1}).TerminateAfter(1), want: 1},
+		{client: es.DB().Term(esql.F{"Number": 1}).Preference("_local"), want: 3},
+		{client: es.DB().Term(esql.F{"Number": 9}), want: 0},
+		// the query is in the join
+		{client: es.DB().Term(esql.F{"Number": 1}).Joins("nested", "As3"), want: 0},
+	}
+
+	for _, v := range cases {
+		got, err := v.client.CountDocs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.want {
+			t.Fatal(v.want, got)
+		}
+	}
+}
+
+func TestSearchOptions(t *testing.T) {
+	c := es.DB().Where(esql.F{"Name": "test"}).
+		TrackTotalHits(true).MinScore(0.5).SearchTimeout("2s").SearchType("dfs_query_then_fetch").
+		StoredFields("_source", "Name").DocvalueFields("Level").DocvalueFields("JoinDate", "epoch_millis").
+		Version(true).SeqNoPrimaryTerm(true).Explain(true).TerminateAfter(10)
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+
+	got := esql.F{}
+	json.Unmarshal([]byte(c.Template()), &got)
+	for _, k := range []string{"track_total_hits", "min_score", "timeout", "stored_fields", "docvalue_fields", "version", "seq_no_primary_term", "explain"} {
+		if got[k] == nil {
+			t.Fatal("missing ", k)
+		}
+	}
+	for _, k := range []string{"search_type", "terminate_after"} {
+		if got[k] != nil {
+			t.Fatal(k, " should be in path")
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	c := es.DB().Order(esql.F{"Name": "desc", "Age": "asc", "Level": esql.F{"order": "asc"}}).
+		OrderBy("Level", esql.Desc, esql.F{"missing": "_last", "unmapped_type": "long"}).
+		ThenBy("Mysql.Level", esql.Asc, esql.F{"mode": "avg", "nested": esql.F{"path": "Mysql", "filter": esql.F{"term": esql.F{"Mysql.Name": "go"}}}}).
+		ThenBy("_score").
+		OrderScript("number", esql.F{"source": "doc['Level'].value * params.factor", "params": esql.F{"factor": 1.1}}, esql.Desc).
+		OrderGeoDistance("Location", esql.GeoPoint{Lat: 40, Lon: -70}, esql.Asc, "km", "arc")
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+
+	var got struct {
+		Sort []interface{} `json:"sort"`
+	}
+	json.Unmarshal([]byte(c.Template()), &got)
+	if len(got.Sort) != 5 || got.Sort[2] != "_score" {
+		t.Fatal(got.Sort)
+	}
+
+	c = es.DB().Order(esql.F{"Name": "desc", "Age": "asc", "Level": "asc"})
+	want := `{"sort":[{"Age":"asc"},{"Level":"asc"},{"Name":"desc"}]}`
+	if c.Serialize().Template() != want {
+		t.Fatal(c.Template())
+	}
+}
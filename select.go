@@ -0,0 +1,524 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strings"
+)
+
+// QueryBuckets the max buckets of terms aggregations of Query, when LIMIT can't cut the buckets:
+// no LIMIT, HAVING, or more than one GROUP BY field. it's divided across the levels of GROUP BY,
+// every level of n fields has the nth root of it, e.g 100 for 2 fields, to be in search.max_buckets
+var QueryBuckets = 10000
+
+// the aggregate functions of sql and their metric aggregations
+var sqlAggregates = map[string]string{
+	"COUNT": "value_count",
+	"AVG":   "avg",
+	"SUM":   "sum",
+	"MIN":   "min",
+	"MAX":   "max",
+}
+
+// sqlColumn a column of SELECT, a field or an aggregate function
+type sqlColumn struct {
+	name   string // alias or expression, e.g avg(price)
+	fn     string // metric aggregation, empty for field
+	field  string
+	hidden bool // only used by HAVING or ORDER BY, not in result
+}
+
+// path the buckets_path of aggregate column
+func (col *sqlColumn) path() string {
+	if col.fn == "value_count" && col.field == "*" {
+		return "_count"
+	}
+	return "metric_" + col.fn + "_" + col.field
+}
+
+type sqlOrder struct {
+	column *sqlColumn
+	desc   bool
+}
+
+// sqlSelect the plan of SELECT, kept by client to flatten the response into table
+type sqlSelect struct {
+	columns []*sqlColumn
+	groups  []string
+	orders  []sqlOrder
+	having  F // bucket_selector
+	offset  int
+	size    int
+}
+
+func (s *sqlSelect) aggregate() bool {
+	if len(s.groups) > 0 {
+		return true
+	}
+	for _, v := range s.columns {
+		if v.fn != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// column := * | field [AS alias] | fn([DISTINCT] field|*) [AS alias]
+func (p *sqlParser) column() *sqlColumn {
+	if p.acceptPunct("*") {
+		return &sqlColumn{name: "*", field: "*"}
+	}
+	col := p.operand()
+	if p.acceptKeyword("AS") {
+		col.name = p.ident()
+	}
+	return col
+}
+
+// operand := field | fn([DISTINCT] field|*)
+func (p *sqlParser) operand() *sqlColumn {
+	name := p.ident()
+	if !p.acceptPunct("(") {
+		return &sqlColumn{name: name, field: name}
+	}
+
+	fn, ok := sqlAggregates[strings.ToUpper(name)]
+	if !ok {
+		sqlFail("unknown function %s", name)
+	}
+	expr := strings.ToLower(name) + "("
+	if p.acceptKeyword("DISTINCT") {
+		if fn != "value_count" {
+			sqlFail("DISTINCT only works with count")
+		}
+		fn, expr = "cardinality", expr+"distinct "
+	}
+
+	field := "*"
+	if !p.acceptPunct("*") {
+		field = p.ident()
+	} else if fn != "value_count" {
+		sqlFail("%s(*) is not supported", name)
+	}
+	p.expectPunct(")")
+	return &sqlColumn{name: expr + field + ")", fn: fn, field: field}
+}
+
+// resolve finds the column of operand in SELECT by alias or expression, adds it as hidden if not existed.
+func (s *sqlSelect) resolve(op *sqlColumn) *sqlColumn {
+	for _, v := range s.columns {
+		if op.fn == "" && v.name == op.name || op.fn != "" && v.fn == op.fn && v.field == op.field {
+			return v
+		}
+	}
+	if op.fn == "" && len(s.groups) > 0 && !s.grouped(op.field) {
+		sqlFail("%s should be an alias, aggregate or in GROUP BY", op.name)
+	}
+	op.hidden = true
+	s.columns = append(s.columns, op)
+	return op
+}
+
+func (s *sqlSelect) grouped(field string) bool {
+	for _, g := range s.groups {
+		if g == field {
+			return true
+		}
+	}
+	return false
+}
+
+// having := hand (OR hand)*, it makes painless script of bucket_selector
+func (p *sqlParser) having(s *sqlSelect, paths F) string {
+	script := p.havingAnd(s, paths)
+	for p.acceptKeyword("OR") {
+		script += " || " + p.havingAnd(s, paths)
+	}
+	return script
+}
+
+// hand := hnot (AND hnot)*
+func (p *sqlParser) havingAnd(s *sqlSelect, paths F) string {
+	script := p.havingNot(s, paths)
+	for p.acceptKeyword("AND") {
+		script += " && " + p.havingNot(s, paths)
+	}
+	return script
+}
+
+// hnot := NOT hnot | ( having ) | operand op number
+func (p *sqlParser) havingNot(s *sqlSelect, paths F) string {
+	if p.acceptKeyword("NOT") {
+		return "!(" + p.havingNot(s, paths) + ")"
+	}
+	if p.acceptPunct("(") {
+		script := p.having(s, paths)
+		p.expectPunct(")")
+		return "(" + script + ")"
+	}
+
+	col := s.resolve(p.operand())
+	if col.fn == "" {
+		sqlFail("HAVING on %s should be an aggregate", col.name)
+	}
+	name := fmt.Sprintf("v%d", len(paths))
+	for k, v := range paths {
+		if v == col.path() {
+			name = k
+		}
+	}
+	paths[name] = col.path()
+
+	t := p.peek()
+	ops := map[string]string{"=": "==", "!=": "!=", "<>": "!=", ">": ">", ">=": ">=", "<": "<", "<=": "<="}
+	op, ok := ops[t.text]
+	if t.kind != sqlOp || !ok {
+		p.unexpected("comparison")
+	}
+	p.pos++
+	v := p.value()
+	if _, ok := toFloat(v); !ok {
+		sqlFail("HAVING should compare with number, got %v", v)
+	}
+	return fmt.Sprintf("params.%s %s %v", name, op, v)
+}
+
+// Query makes a client by a sql SELECT, the result is read as a table by Client.Rows
+// SELECT columns FROM index [WHERE condition] [GROUP BY field, ...] [HAVING condition]
+// [ORDER BY column [ASC|DESC], ...] [LIMIT [offset,] size]
+// columns: *, field, count(*), count(field), count(distinct field), avg, sum, min, max, with optional AS alias.
+// GROUP BY makes nested terms aggregations group_{field}, aggregates are metrics metric_{fn}_{field} on the
+// innermost group, HAVING is a bucket_selector, ORDER BY an aggregate orders the buckets. without GROUP BY,
+// the fields are the _source projection of hits. WHERE is the same as Client.SQL.
+// with GROUP BY, LIMIT is the offset and size of rows after HAVING, a single terms aggregation without HAVING
+// is cut by LIMIT, or the terms are sized by QueryBuckets.
+// e.g  Query("SELECT country, city, avg(price), count(*) FROM products WHERE stock > ? GROUP BY country, city "+
+// "HAVING count(*) > 10 ORDER BY avg(price) DESC LIMIT 20", 0).Rows()
+func Query(query string, args ...interface{}) *Client {
+	c := DB("")
+	c.Error = catchSQL(func() {
+		p := newSQLParser(query, args)
+		s := &sqlSelect{size: -1}
+		p.expectKeyword("SELECT")
+		for {
+			s.columns = append(s.columns, p.column())
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+
+		p.expectKeyword("FROM")
+		c.hostDB.Path = path.Join(c.hostDB.Path, p.ident())
+		if p.acceptKeyword("WHERE") {
+			p.expr().where(c)
+		}
+
+		if p.acceptKeyword("GROUP") {
+			p.expectKeyword("BY")
+			for {
+				s.groups = append(s.groups, p.ident())
+				if !p.acceptPunct(",") {
+					break
+				}
+			}
+			for _, v := range s.columns {
+				if v.fn == "" && !s.grouped(v.field) {
+					sqlFail("%s should be in GROUP BY", v.name)
+				}
+			}
+		}
+
+		if p.acceptKeyword("HAVING") {
+			if len(s.groups) == 0 {
+				sqlFail("HAVING needs GROUP BY")
+			}
+			paths := F{}
+			script := p.having(s, paths)
+			s.having = F{"bucket_selector": F{"buckets_path": paths, "script": script}}
+		}
+
+		if p.acceptKeyword("ORDER") {
+			p.expectKeyword("BY")
+			for {
+				order := sqlOrder{column: s.resolve(p.operand())}
+				if p.acceptKeyword("DESC") {
+					order.desc = true
+				} else {
+					p.acceptKeyword("ASC")
+				}
+				s.orders = append(s.orders, order)
+				if !p.acceptPunct(",") {
+					break
+				}
+			}
+		}
+
+		s.offset, s.size = p.limit()
+		p.end()
+		s.build(c)
+	})
+	return c
+}
+
+// build sets the plan on client
+func (s *sqlSelect) build(c *Client) {
+	c.selects = s
+	if !s.aggregate() {
+		fields := []string{}
+		for _, v := range s.columns {
+			if v.hidden {
+				continue
+			}
+			if v.field == "*" {
+				fields = nil
+				break
+			}
+			fields = append(fields, v.field)
+		}
+		if fields != nil {
+			c.Source(fields)
+		}
+		for _, v := range s.orders {
+			order := Asc
+			if v.desc {
+				order = Desc
+			}
+			c.ThenBy(v.column.field, order)
+		}
+		if s.offset > 0 {
+			c.Limit(s.offset, s.size)
+		} else if s.size >= 0 {
+			c.Limit(s.size)
+		}
+		return
+	}
+
+	c.Limit(0)
+	if len(s.groups) == 0 {
+		// count(*) is the total hits
+		c.TrackTotalHits(true)
+	}
+	on := ""
+	for n, field := range s.groups {
+		_set := F{"size": s.bucketSize()}
+		orders := []F{}
+		for _, v := range s.orders {
+			dir := Asc
+			if v.desc {
+				dir = Desc
+			}
+			if v.column.fn == "" && v.column.field == field {
+				orders = append(orders, F{"_key": dir})
+			} else if v.column.fn != "" && n == len(s.groups)-1 {
+				orders = append(orders, F{v.column.path(): dir})
+			}
+		}
+		if len(orders) > 0 {
+			_set["order"] = orders
+		}
+
+		if on == "" {
+			c.setGroups("terms", field, []F{_set})
+		} else {
+			c.setSubGroup(on, "terms", field, []F{_set})
+		}
+		on = "group_" + field
+	}
+
+	for _, v := range s.columns {
+		if v.fn == "" || v.path() == "_count" {
+			continue
+		}
+		c.setMetricsAs(v.path(), v.fn, v.field, []interface{}{on})
+	}
+
+	if s.having != nil {
+		g := c.findGroup(c.groups, on)
+		if g["aggs"] == nil {
+			g["aggs"] = F{}
+		}
+		g["aggs"].(F)["having"] = s.having
+	}
+}
+
+// bucketSize the size of every terms aggregation of GROUP BY
+func (s *sqlSelect) bucketSize() int {
+	if s.size >= 0 && s.having == nil && len(s.groups) == 1 {
+		// the buckets are in the order of rows
+		return s.offset + s.size
+	}
+	size := int(math.Pow(float64(QueryBuckets), 1/float64(len(s.groups))) + 1e-9)
+	if size < 1 {
+		size = 1
+	}
+	return size
+}
+
+// Rows runs the client made by Query, and reads the response as a table
+func (c *Client) Rows() (*Table, error) {
+	s := c.selects
+	if s == nil {
+		return nil, fmt.Errorf("esql: Rows only works with the client made by Query")
+	}
+
+	var resp struct {
+		Hits struct {
+			Total Total `json:"total"`
+			Hits  []Hit `json:"hits"`
+		} `json:"hits"`
+		Aggregations map[string]interface{} `json:"aggregations"`
+	}
+	if err := c.Find(&resp).Error; err != nil {
+		return nil, err
+	}
+
+	t := &Table{}
+	if !s.aggregate() {
+		s.hitRows(t, resp.Hits.Hits)
+		t.typeColumns()
+		return t, nil
+	}
+
+	if len(s.groups) == 0 {
+		bucket := resp.Aggregations
+		if bucket == nil {
+			bucket = map[string]interface{}{}
+		}
+		bucket["doc_count"] = resp.Hits.Total.Value
+		t.Rows = append(t.Rows, s.row(bucket, nil))
+	} else {
+		s.bucketRows(t, resp.Aggregations, nil)
+	}
+
+	columns, desc := []int{}, []bool{}
+	for _, o := range s.orders {
+		for i, v := range s.columns {
+			if v == o.column {
+				columns, desc = append(columns, i), append(desc, o.desc)
+			}
+		}
+	}
+	sortRows(t.Rows, columns, desc)
+
+	if s.offset >= len(t.Rows) {
+		t.Rows = t.Rows[:0]
+	} else {
+		t.Rows = t.Rows[s.offset:]
+	}
+	if s.size >= 0 && s.size < len(t.Rows) {
+		t.Rows = t.Rows[:s.size]
+	}
+
+	// strip the hidden columns
+	for i, row := range t.Rows {
+		visible := []interface{}{}
+		for n, v := range s.columns {
+			if !v.hidden {
+				visible = append(visible, row[n])
+			}
+		}
+		t.Rows[i] = visible
+	}
+	for _, v := range s.columns {
+		if !v.hidden {
+			column := Column{Name: v.name}
+			switch v.fn {
+			case "value_count", "cardinality":
+				column.Type = "long"
+			case "avg", "sum", "min", "max":
+				column.Type = "double"
+			}
+			t.Columns = append(t.Columns, column)
+		}
+	}
+	t.typeColumns()
+	return t, nil
+}
+
+// bucketRows walks the nested terms aggregations, a row for every bucket of the innermost group
+func (s *sqlSelect) bucketRows(t *Table, aggs map[string]interface{}, keys []interface{}) {
+	if len(keys) == len(s.groups) {
+		t.Rows = append(t.Rows, s.row(aggs, keys))
+		return
+	}
+
+	group, _ := aggs["group_"+s.groups[len(keys)]].(map[string]interface{})
+	buckets, _ := group["buckets"].([]interface{})
+	for _, v := range buckets {
+		bucket, _ := v.(map[string]interface{})
+		key := bucket["key"]
+		if str, ok := bucket["key_as_string"]; ok {
+			key = str
+		}
+		s.bucketRows(t, bucket, append(keys[:len(keys):len(keys)], key))
+	}
+}
+
+func (s *sqlSelect) row(bucket map[string]interface{}, keys []interface{}) []interface{} {
+	row := []interface{}{}
+	for _, v := range s.columns {
+		switch {
+		case v.fn == "":
+			var key interface{}
+			for n, g := range s.groups {
+				if g == v.field {
+					key = keys[n]
+				}
+			}
+			row = append(row, key)
+		case v.path() == "_count":
+			row = append(row, toCount(bucket["doc_count"]))
+		default:
+			metric, _ := bucket[v.path()].(map[string]interface{})
+			value := metric["value"]
+			if v.fn == "value_count" || v.fn == "cardinality" {
+				value = toCount(value)
+			}
+			row = append(row, value)
+		}
+	}
+	return row
+}
+
+func toCount(v interface{}) interface{} {
+	if f, ok := v.(float64); ok {
+		return int64(f)
+	}
+	return v
+}
+
+// hitRows a row for every hit, * expands to all fields of the first hit
+func (s *sqlSelect) hitRows(t *Table, hits []Hit) {
+	sources := make([]map[string]interface{}, len(hits))
+	for i, v := range hits {
+		json.Unmarshal(v.Source, &sources[i])
+	}
+
+	fields := []string{}
+	for _, v := range s.columns {
+		if v.hidden {
+			continue
+		}
+		if v.field != "*" {
+			fields = append(fields, v.field)
+			t.Columns = append(t.Columns, Column{Name: v.name})
+			continue
+		}
+		if len(sources) > 0 {
+			for _, p := range sourcePaths("", sources[0]) {
+				fields = append(fields, p)
+				t.Columns = append(t.Columns, Column{Name: p})
+			}
+		}
+	}
+
+	for _, source := range sources {
+		row := make([]interface{}, len(fields))
+		for i, f := range fields {
+			row[i] = sourceValue(source, f)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+}
@@ -0,0 +1,118 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestQueryTemplate(t *testing.T) {
+	cases := []struct {
+		sql  string
+		want string
+	}{
+		{
+			sql:  "SELECT country, city, avg(price), count(*) FROM products WHERE stock > 0 GROUP BY country, city HAVING count(*) > 10 ORDER BY avg(price) DESC LIMIT 20",
+			want: `{"aggs":{"group_country":{"aggs":{"group_city":{"aggs":{"having":{"bucket_selector":{"buckets_path":{"v0":"_count"},"script":"params.v0 \u003e 10"}},"metric_avg_price":{"avg":{"field":"price"}}},"terms":{"field":"city","order":[{"metric_avg_price":"desc"}],"size":100}}},"terms":{"field":"country","size":100}}},"query":{"bool":{"filter":[{"range":{"stock":{"gt":0}}}]}},"size":0}`,
+		},
+		{
+			sql:  "SELECT country, sum(price) AS total, count(distinct city) FROM products GROUP BY country HAVING total > 5 AND NOT max(price) >= 100 ORDER BY country DESC",
+			want: `{"aggs":{"group_country":{"aggs":{"having":{"bucket_selector":{"buckets_path":{"v0":"metric_sum_price","v1":"metric_max_price"},"script":"params.v0 \u003e 5 \u0026\u0026 !(params.v1 \u003e= 100)"}},"metric_cardinality_city":{"cardinality":{"field":"city"}},"metric_max_price":{"max":{"field":"price"}},"metric_sum_price":{"sum":{"field":"price"}}},"terms":{"field":"country","order":[{"_key":"desc"}],"size":10000}}},"size":0}`,
+		},
+		{
+			sql:  "SELECT country, count(*) FROM products GROUP BY country ORDER BY count(*) DESC LIMIT 5, 10",
+			want: `{"aggs":{"group_country":{"terms":{"field":"country","order":[{"_count":"desc"}],"size":15}}},"size":0}`,
+		},
+		{
+			sql:  "SELECT name, address.city AS city FROM products WHERE tags IN ('a', 'b') ORDER BY price DESC LIMIT 5, 10",
+			want: `{"_source":["name","address.city"],"from":5,"query":{"bool":{"filter":[{"terms":{"tags":["a","b"]}}]}},"size":10,"sort":[{"price":{"order":"desc"}}]}`,
+		},
+	}
+
+	for _, v := range cases {
+		c := esql.Query(v.sql)
+		if err := c.Serialize().Error; err != nil {
+			t.Fatal(v.sql, err)
+		}
+		if c.Template() != v.want {
+			t.Fatal(v.sql, c.Template())
+		}
+	}
+
+	// the placeholders of all kinds of numbers
+	for _, v := range []interface{}{int32(1), uint(1), float32(1.5), int8(1)} {
+		if err := esql.Query("SELECT country FROM products GROUP BY country HAVING count(*) > ?", v).Error; err != nil {
+			t.Fatal(v, err)
+		}
+	}
+
+	for _, v := range []string{
+		"SELECT name FROM products GROUP BY country",
+		"SELECT country FROM products HAVING count(*) > 1",
+		"SELECT foo(x) FROM products",
+		"SELECT country FROM products GROUP BY country HAVING country > 1",
+		"SELECT avg(*) FROM products",
+	} {
+		if err := esql.Query(v).Error; err == nil {
+			t.Fatal(v)
+		}
+	}
+}
+
+func TestQueryRows(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "this is a test", Level: 2, Number: 1},
+		mysql{Name: "this is a test", Level: 2, Number: 1},
+		mysql{Name: "another one", Level: 3, Number: 1},
+		mysql{Name: "another one", Level: 3, Number: 1},
+	)
+
+	table, err := esql.Query("SELECT Level, count(*) AS total, sum(Level) FROM esql WHERE Number = ? "+
+		"GROUP BY Level HAVING count(*) > 1 ORDER BY Level DESC", 1).Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(table)
+	if len(table.Columns) != 3 || table.Columns[1].Name != "total" || len(table.Rows) != 2 {
+		t.Fatal(table)
+	}
+	if table.Rows[0][0].(float64) != 3 || table.Rows[0][1].(int64) != 2 || table.Rows[0][2].(float64) != 6 {
+		t.Fatal(table.Rows[0])
+	}
+
+	// more than 10 groups, HAVING drops the first buckets of LIMIT
+	es.DB().Term(esql.F{"Number": 2}).DeleteByQuerry()
+	docs := []interface{}{}
+	for n := 1; n <= 12; n++ {
+		docs = append(docs, mysql{Name: "bucket", Level: n, Number: 2})
+	}
+	initRecords(docs...)
+	table, err = esql.Query("SELECT Level FROM esql WHERE Number = 2 GROUP BY Level").Rows()
+	if err != nil || len(table.Rows) != 12 {
+		t.Fatal(err, table)
+	}
+	table, err = esql.Query("SELECT Level FROM esql WHERE Number = 2 GROUP BY Level "+
+		"HAVING max(Level) > 10 ORDER BY Level LIMIT 2").Rows()
+	if err != nil || len(table.Rows) != 2 || table.Rows[0][0].(float64) != 11 {
+		t.Fatal(err, table)
+	}
+	es.DB().Term(esql.F{"Number": 2}).DeleteByQuerry()
+
+	table, err = esql.Query("SELECT Name, Level FROM esql WHERE Number = 1 AND Level = 1").Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Rows) != 1 || table.Rows[0][0] != "this is a test" {
+		t.Fatal(table)
+	}
+
+	table, err = esql.Query("SELECT count(*), max(Level) FROM esql WHERE Number = 1").Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Rows[0][0].(int64) != 5 || table.Rows[0][1].(float64) != 3 {
+		t.Fatal(table)
+	}
+}
@@ -0,0 +1,112 @@
+package esql
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sync"
+)
+
+// Progress the progress of SlicedScroll, Total is known after the first batch of every slice
+type Progress struct {
+	Done  int64
+	Total int64
+}
+
+// SlicedScroll scrolls the hits of query in parallel, every one of slices runs its own scroll with batches of size,
+// fn is called on every batch concurrently, it should be safe for concurrent use.
+// progress is called after every batch in order, it can be nil. the first error of slices or fn cancels the others,
+// and it is returned. the cancellation of context also stops the slices, see WithContext.
+// e.g es.DB().Term(F{"Level": 1}).SlicedScroll(4, 1000, "1m", export, func(p Progress) { log.Println(p) })
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-scroll.html#sliced-scroll
+func (c *Client) SlicedScroll(slices, size int, keepAlive string, fn func(hits []Hit) error, progress func(Progress)) error {
+	if _, ok := c.search["track_total_hits"]; !ok {
+		c.TrackTotalHits(true)
+	}
+	if c.Serialize().Error != nil {
+		return c.Error
+	}
+	var body F
+	if c.Error = json.Unmarshal([]byte(c.template), &body); c.Error != nil {
+		return c.Error
+	}
+
+	parent := c.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		done     int64
+		total    int64
+		mu       sync.Mutex
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	if slices < 1 {
+		slices = 1
+	}
+	for id := 0; id < slices; id++ {
+		w := c.slice(body, id, slices)
+		w.ctx = ctx
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			first := true
+			err := w.scroll(size, keepAlive, func(result *SearchResult) error {
+				if first {
+					first = false
+					mu.Lock()
+					total += result.Total.Value
+					mu.Unlock()
+				}
+				if err := fn(result.Hits); err != nil {
+					return err
+				}
+				// the counting and report are in one lock, so the progress never goes back
+				mu.Lock()
+				defer mu.Unlock()
+				done += int64(len(result.Hits))
+				if progress != nil {
+					progress(Progress{Done: done, Total: total})
+				}
+				return nil
+			})
+			if err != nil {
+				fail(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr == nil && parent.Err() != nil {
+		return parent.Err()
+	}
+	return firstErr
+}
+
+// slice a client of the slice id with the search body, on the index of client
+func (c *Client) slice(body F, id, max int) *Client {
+	w := DB("")
+	_url := *c.hostDB
+	w.hostDB = &_url
+	w.queries = url.Values{}
+	for k, v := range c.queries {
+		w.queries[k] = append([]string{}, v...)
+	}
+	w.search.Append(body)
+	if max > 1 {
+		w.search["slice"] = F{"id": id, "max": max}
+	}
+	return w
+}
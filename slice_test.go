@@ -0,0 +1,47 @@
+package esql_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestSlicedScroll(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	records := []interface{}{}
+	for i := 0; i < 10; i++ {
+		records = append(records, mysql{Name: "this is a test", Level: i, Number: 1})
+	}
+	initRecords(records...)
+
+	var mu sync.Mutex
+	ids := map[string]bool{}
+	var last esql.Progress
+	err := es.DB().Term(esql.F{"Number": 1}).SlicedScroll(2, 3, "1m", func(hits []esql.Hit) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, h := range hits {
+			ids[h.ID] = true
+		}
+		return nil
+	}, func(p esql.Progress) {
+		if p.Done < last.Done || p.Total < last.Total {
+			t.Error("progress goes back", last, p)
+		}
+		last = p
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 10 || last != (esql.Progress{Done: 10, Total: 10}) {
+		t.Fatal(len(ids), last)
+	}
+
+	stop := errors.New("stop")
+	err = es.DB().Term(esql.F{"Number": 1}).SlicedScroll(2, 3, "1m", func(hits []esql.Hit) error { return stop }, nil)
+	if err != stop {
+		t.Fatal(err)
+	}
+}
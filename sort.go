@@ -0,0 +1,57 @@
+package esql
+
+import "fmt"
+
+// the directions of sort
+const (
+	Asc  = "asc"
+	Desc = "desc"
+)
+
+// OrderBy resets the sorts of client with field, the following ThenBy adds more sorts in order.
+// i the direction Asc or Desc, and F of other settings: missing, mode, unmapped_type, nested
+// e.g  OrderBy("price", Desc, F{"missing": "_last", "mode": "avg"}).ThenBy("_score")
+// nested sort: OrderBy("offer.price", Asc, F{"nested": F{"path": "offer", "filter": F{"term": F{"offer.color": "blue"}}}})
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-sort.html
+func (c *Client) OrderBy(field string, i ...interface{}) *Client {
+	c.sorts = nil
+	return c.ThenBy(field, i...)
+}
+
+// ThenBy appends a sort after the sorts of client, the settings are same as OrderBy
+func (c *Client) ThenBy(field string, i ...interface{}) *Client {
+	_set := c.sortSetting(i)
+	if len(_set) == 0 {
+		c.sorts = append(c.sorts, field)
+		return c
+	}
+	c.sorts = append(c.sorts, F{field: _set})
+	return c
+}
+
+// OrderScript appends a sort on the value of script, types: number or string
+// e.g  OrderScript("number", F{"source": "doc['Level'].value * params.factor", "params": F{"factor": 1.1}}, Desc)
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/search-request-sort.html#_script_based_sorting
+func (c *Client) OrderScript(types string, script F, i ...interface{}) *Client {
+	_set := c.sortSetting(i)
+	_set["type"], _set["script"] = types, script
+	c.sorts = append(c.sorts, F{"_script": _set})
+	return c
+}
+
+func (c *Client) sortSetting(i []interface{}) F {
+	_set := F{}
+	for _, v := range i {
+		switch s := v.(type) {
+		case string:
+			_set["order"] = s
+		case F:
+			_set.Append(s)
+		case map[string]interface{}:
+			_set.Append(s)
+		default:
+			c.Error = fmt.Errorf("esql: illegal sort setting %v", v)
+		}
+	}
+	return _set
+}
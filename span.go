@@ -0,0 +1,77 @@
+package esql
+
+// span queries are low-level positional queries, they are built as F and can be
+// composed with each other, then put into any bool clause by Span or Clause.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/span-queries.html
+
+// SpanTerm {"span_term" : { "field" : "value" }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-term-query.html
+func SpanTerm(field string, value interface{}) F {
+	return F{"span_term": F{field: value}}
+}
+
+// SpanNear {"span_near" : { "clauses" : [...], "slop" : 12, "in_order" : false }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-near-query.html
+func SpanNear(slop int, inOrder bool, clauses ...F) F {
+	return F{"span_near": F{"clauses": spanClauses(clauses), "slop": slop, "in_order": inOrder}}
+}
+
+// SpanOr {"span_or" : { "clauses" : [...] }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-or-query.html
+func SpanOr(clauses ...F) F {
+	return F{"span_or": F{"clauses": spanClauses(clauses)}}
+}
+
+// SpanNot {"span_not" : { "include" : {...}, "exclude" : {...} }}
+// i other settings, e.g F{"pre": 1, "post": 1} or F{"dist": 2}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-not-query.html
+func SpanNot(include, exclude F, i ...F) F {
+	_set := F{}
+	for _, v := range i {
+		_set.Append(v)
+	}
+	_set["include"], _set["exclude"] = include, exclude
+	return F{"span_not": _set}
+}
+
+// SpanFirst {"span_first" : { "match" : {...}, "end" : 3 }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-first-query.html
+func SpanFirst(match F, end int) F {
+	return F{"span_first": F{"match": match, "end": end}}
+}
+
+// SpanContaining {"span_containing" : { "big" : {...}, "little" : {...} }}
+// returns matches which enclose another span query.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-containing-query.html
+func SpanContaining(big, little F) F {
+	return F{"span_containing": F{"big": big, "little": little}}
+}
+
+// SpanWithin {"span_within" : { "big" : {...}, "little" : {...} }}
+// returns matches which are enclosed inside another span query.
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-within-query.html
+func SpanWithin(big, little F) F {
+	return F{"span_within": F{"big": big, "little": little}}
+}
+
+// SpanMulti wraps a multi term query as a span query.
+// types: wildcard, prefix, regexp, fuzzy or range
+// e.g  SpanMulti("prefix", "user", F{"value": "ki"})
+// {"span_multi" : { "match" : { "prefix" : { "user" : { "value" : "ki" } } } }}
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/query-dsl-span-multi-term-query.html
+func SpanMulti(types, field string, value interface{}) F {
+	return F{"span_multi": F{"match": F{types: F{field: value}}}}
+}
+
+func spanClauses(clauses []F) []F {
+	if clauses == nil {
+		return []F{}
+	}
+	return clauses
+}
+
+// Span puts span queries into must
+// e.g Span(SpanNear(1, true, SpanTerm("Name", "this"), SpanTerm("Name", "test")))
+func (c *Client) Span(i ...F) *Client {
+	return c.Clause("must", i...)
+}
@@ -0,0 +1,61 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+/*
+{
+    "query": {
+        "bool": {
+            "must": [
+                {
+                    "span_near": {
+                        "clauses": [
+                            {"span_term": {"Name": "this"}},
+                            {"span_multi": {"match": {"prefix": {"Name": {"value": "te"}}}}}
+                        ],
+                        "in_order": true,
+                        "slop": 2
+                    }
+                }
+            ]
+        }
+    }
+}
+*/
+func TestSpanNear(t *testing.T) {
+	c := es.DB()
+	near := esql.SpanNear(2, true,
+		esql.SpanTerm("Name", "this"),
+		esql.SpanMulti("prefix", "Name", esql.F{"value": "te"}))
+	if err := c.Span(near).Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log(c.Template())
+}
+
+func TestSpanInClauses(t *testing.T) {
+	c := es.DB()
+	first := esql.SpanFirst(esql.SpanOr(esql.SpanTerm("Name", "this"), esql.SpanTerm("Name", "that")), 3)
+	within := esql.SpanWithin(
+		esql.SpanNear(5, false, esql.SpanTerm("Name", "this"), esql.SpanTerm("Name", "test")),
+		esql.SpanTerm("Name", "is"))
+	not := esql.SpanNot(esql.SpanTerm("Name", "test"), esql.SpanTerm("Name", "must"), esql.F{"dist": 1})
+	containing := esql.SpanContaining(
+		esql.SpanNear(5, false, esql.SpanTerm("Name", "this"), esql.SpanTerm("Name", "test")),
+		esql.SpanTerm("Name", "is"))
+
+	c.Clause("should", first, containing).Clause("filter", within).Clause("must_not", not)
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Log(c.Template())
+
+	if err := es.DB().Clause("must-not", not).Serialize().Error; err == nil {
+		t.Fatal("unknown clause should fail")
+	}
+}
@@ -0,0 +1,562 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// the kinds of sql token
+const (
+	sqlEOF = iota
+	sqlIdent
+	sqlString
+	sqlNumber
+	sqlParam
+	sqlOp
+	sqlPunct
+)
+
+var sqlKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true, "LIKE": true,
+	"BETWEEN": true, "ORDER": true, "BY": true, "ASC": true, "DESC": true, "LIMIT": true,
+	"OFFSET": true, "SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true,
+	"AS": true, "TRUE": true, "FALSE": true, "DISTINCT": true,
+}
+
+type sqlToken struct {
+	kind   int
+	text   string
+	quoted bool // `field` or "field"
+	pos    int
+}
+
+// sqlError is raised by panic in parser, and recovered as Client.Error
+type sqlError struct {
+	err error
+}
+
+// lexSQL splits str into tokens
+func lexSQL(str string) (tokens []sqlToken) {
+	rs := []rune(str)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '\'':
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(rs) {
+					sqlFail("unterminated string at %d", start)
+				}
+				if rs[i] == '\'' {
+					if i+1 < len(rs) && rs[i+1] == '\'' {
+						b.WriteRune('\'')
+						i++
+						continue
+					}
+					i++
+					break
+				}
+				b.WriteRune(rs[i])
+			}
+			tokens = append(tokens, sqlToken{kind: sqlString, text: b.String(), pos: start})
+			continue
+		case r == '`' || r == '"':
+			end := i + 1
+			for end < len(rs) && rs[end] != r {
+				end++
+			}
+			if end >= len(rs) {
+				sqlFail("unterminated identifier at %d", start)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: string(rs[i+1 : end]), quoted: true, pos: start})
+			i = end + 1
+			continue
+		case unicode.IsDigit(r) || r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1]):
+			for i < len(rs) && (unicode.IsDigit(rs[i]) || rs[i] == '.' ||
+				(rs[i] == 'e' || rs[i] == 'E') ||
+				(rs[i] == '-' || rs[i] == '+') && (rs[i-1] == 'e' || rs[i-1] == 'E')) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: string(rs[start:i]), pos: start})
+			continue
+		case unicode.IsLetter(r) || r == '_' || r == '@':
+			for i < len(rs) && (unicode.IsLetter(rs[i]) || unicode.IsDigit(rs[i]) || strings.ContainsRune("_.@", rs[i])) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdent, text: string(rs[start:i]), pos: start})
+			continue
+		case r == '?':
+			tokens = append(tokens, sqlToken{kind: sqlParam, text: "?", pos: start})
+		case strings.ContainsRune("(),*", r):
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(r), pos: start})
+		case r == '<' || r == '>' || r == '!':
+			if i+1 < len(rs) && (rs[i+1] == '=' || r == '<' && rs[i+1] == '>') {
+				i++
+			} else if r == '!' {
+				sqlFail("unexpected ! at %d", start)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlOp, text: string(rs[start : i+1]), pos: start})
+		case strings.ContainsRune("=-+", r):
+			tokens = append(tokens, sqlToken{kind: sqlOp, text: string(r), pos: start})
+		default:
+			sqlFail("unexpected %q at %d", r, start)
+		}
+		i++
+	}
+	return append(tokens, sqlToken{kind: sqlEOF, pos: len(rs)})
+}
+
+func sqlFail(format string, i ...interface{}) {
+	panic(sqlError{fmt.Errorf("esql: sql "+format, i...)})
+}
+
+// catchSQL runs parse, and turns the panic of parser into err
+func catchSQL(parse func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(sqlError)
+			if !ok {
+				panic(r)
+			}
+			err = e.err
+		}
+	}()
+	parse()
+	return nil
+}
+
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+	args   []interface{}
+	nArg   int
+}
+
+func newSQLParser(str string, args []interface{}) *sqlParser {
+	return &sqlParser{tokens: lexSQL(str), args: args}
+}
+
+func (p *sqlParser) peek() sqlToken {
+	return p.tokens[p.pos]
+}
+
+// isKeyword reports whether the current token is one of words
+func (p *sqlParser) isKeyword(words ...string) bool {
+	t := p.peek()
+	if t.kind != sqlIdent || t.quoted {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *sqlParser) acceptKeyword(word string) bool {
+	if p.isKeyword(word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectKeyword(word string) {
+	if !p.acceptKeyword(word) {
+		p.unexpected(word)
+	}
+}
+
+func (p *sqlParser) acceptPunct(punct string) bool {
+	if t := p.peek(); t.kind == sqlPunct && t.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sqlParser) expectPunct(punct string) {
+	if !p.acceptPunct(punct) {
+		p.unexpected(punct)
+	}
+}
+
+func (p *sqlParser) unexpected(want string) {
+	t := p.peek()
+	if t.kind == sqlEOF {
+		sqlFail("syntax error: want %s, got end of input", want)
+	}
+	sqlFail("syntax error: want %s, got %q at %d", want, t.text, t.pos)
+}
+
+// ident a field name, keywords have to be quoted
+func (p *sqlParser) ident() string {
+	t := p.peek()
+	if t.kind != sqlIdent || !t.quoted && sqlKeywords[strings.ToUpper(t.text)] {
+		p.unexpected("field")
+	}
+	p.pos++
+	return t.text
+}
+
+func (p *sqlParser) integer() int {
+	t := p.peek()
+	if t.kind == sqlParam {
+		p.pos++
+		v, ok := p.arg().(int)
+		if !ok {
+			sqlFail("placeholder at %d should be int", t.pos)
+		}
+		return v
+	}
+	n, err := strconv.Atoi(t.text)
+	if t.kind != sqlNumber || err != nil {
+		p.unexpected("integer")
+	}
+	p.pos++
+	return n
+}
+
+func (p *sqlParser) arg() interface{} {
+	if p.nArg >= len(p.args) {
+		sqlFail("not enough args for placeholders")
+	}
+	p.nArg++
+	return p.args[p.nArg-1]
+}
+
+// value a literal or a placeholder, literal numbers are kept as json.Number
+func (p *sqlParser) value() interface{} {
+	t := p.peek()
+	if t.kind == sqlEOF {
+		p.unexpected("value")
+	}
+	p.pos++
+	switch t.kind {
+	case sqlString:
+		return t.text
+	case sqlNumber:
+		if _, err := strconv.ParseFloat(t.text, 64); err != nil {
+			sqlFail("illegal number %s at %d", t.text, t.pos)
+		}
+		return json.Number(t.text)
+	case sqlParam:
+		return p.arg()
+	case sqlOp:
+		if (t.text == "-" || t.text == "+") && p.peek().kind == sqlNumber {
+			n := p.value().(json.Number)
+			return json.Number(strings.TrimPrefix(t.text, "+") + string(n))
+		}
+	case sqlIdent:
+		switch {
+		case t.quoted:
+		case strings.EqualFold(t.text, "TRUE"):
+			return true
+		case strings.EqualFold(t.text, "FALSE"):
+			return false
+		case strings.EqualFold(t.text, "NULL"):
+			return nil
+		}
+	}
+	p.pos--
+	p.unexpected("value")
+	return nil
+}
+
+// values (v1, v2, ...), a placeholder bound to a slice is expanded
+func (p *sqlParser) values() (arr []interface{}) {
+	p.expectPunct("(")
+	for {
+		isParam := p.peek().kind == sqlParam
+		v := p.value()
+		if rv := reflect.ValueOf(v); isParam && v != nil && rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < rv.Len(); i++ {
+				arr = append(arr, rv.Index(i).Interface())
+			}
+		} else {
+			arr = append(arr, v)
+		}
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	p.expectPunct(")")
+	return arr
+}
+
+// sqlNode the tree of condition: and, or, not and leaf query
+type sqlNode struct {
+	op    string
+	nodes []*sqlNode
+	query F
+}
+
+func sqlLeaf(query F) *sqlNode {
+	return &sqlNode{op: "leaf", query: query}
+}
+
+func sqlNot(n *sqlNode) *sqlNode {
+	if n.op == "not" {
+		return n.nodes[0]
+	}
+	return &sqlNode{op: "not", nodes: []*sqlNode{n}}
+}
+
+// expr := and (OR and)*
+func (p *sqlParser) expr() *sqlNode {
+	n := p.and()
+	if !p.isKeyword("OR") {
+		return n
+	}
+	or := &sqlNode{op: "or", nodes: []*sqlNode{n}}
+	for p.acceptKeyword("OR") {
+		or.nodes = append(or.nodes, p.and())
+	}
+	return or
+}
+
+// and := not (AND not)*
+func (p *sqlParser) and() *sqlNode {
+	n := p.not()
+	if !p.isKeyword("AND") {
+		return n
+	}
+	and := &sqlNode{op: "and", nodes: []*sqlNode{n}}
+	for p.acceptKeyword("AND") {
+		and.nodes = append(and.nodes, p.not())
+	}
+	return and
+}
+
+// not := NOT not | ( expr ) | predicate
+func (p *sqlParser) not() *sqlNode {
+	if p.acceptKeyword("NOT") {
+		return sqlNot(p.not())
+	}
+	if p.acceptPunct("(") {
+		n := p.expr()
+		p.expectPunct(")")
+		return n
+	}
+	return p.predicate()
+}
+
+// predicate := field op value | field [NOT] IN (values) | field IS [NOT] NULL
+// | field [NOT] LIKE value | field [NOT] BETWEEN value AND value
+func (p *sqlParser) predicate() *sqlNode {
+	field := p.ident()
+	if t := p.peek(); t.kind == sqlOp {
+		p.pos++
+		return sqlCompare(field, t.text, p.value())
+	}
+
+	if p.acceptKeyword("IS") {
+		not := p.acceptKeyword("NOT")
+		p.expectKeyword("NULL")
+		n := sqlLeaf(F{"exists": F{"field": field}})
+		if not {
+			return n
+		}
+		return sqlNot(n)
+	}
+
+	not := p.acceptKeyword("NOT")
+	var n *sqlNode
+	switch {
+	case p.acceptKeyword("IN"):
+		n = sqlLeaf(F{"terms": F{field: p.values()}})
+	case p.acceptKeyword("LIKE"):
+		v, ok := p.value().(string)
+		if !ok {
+			sqlFail("LIKE on %s should be a string", field)
+		}
+		n = sqlLeaf(F{"wildcard": F{field: likeToWildcard(v)}})
+	case p.acceptKeyword("BETWEEN"):
+		from := p.value()
+		p.expectKeyword("AND")
+		n = sqlLeaf(F{"range": F{field: F{"gte": from, "lte": p.value()}}})
+	default:
+		p.unexpected("operator")
+	}
+	if not {
+		return sqlNot(n)
+	}
+	return n
+}
+
+func sqlCompare(field, op string, v interface{}) *sqlNode {
+	ranges := map[string]string{">": "gt", ">=": "gte", "<": "lt", "<=": "lte"}
+	switch op {
+	case "=":
+		if v == nil {
+			return sqlNot(sqlLeaf(F{"exists": F{"field": field}}))
+		}
+		return sqlLeaf(F{"term": F{field: v}})
+	case "!=", "<>":
+		if v == nil {
+			return sqlLeaf(F{"exists": F{"field": field}})
+		}
+		return sqlNot(sqlLeaf(F{"term": F{field: v}}))
+	}
+	if r, ok := ranges[op]; ok && v != nil {
+		return sqlLeaf(F{"range": F{field: F{r: v}}})
+	}
+	sqlFail("illegal comparison %s %s %v", field, op, v)
+	return nil
+}
+
+// likeToWildcard converts % and _ of LIKE to * and ?, the * and ? of value are escaped.
+func likeToWildcard(like string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range like {
+		switch {
+		case escaped:
+			if r == '*' || r == '?' {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteRune('*')
+		case r == '_':
+			b.WriteRune('?')
+		case r == '*' || r == '?':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// toQuery compiles the node into query dsl
+func (n *sqlNode) toQuery() F {
+	switch n.op {
+	case "and":
+		filter, mustnot := []F{}, []F{}
+		for _, v := range n.nodes {
+			if v.op == "not" {
+				mustnot = append(mustnot, v.nodes[0].toQuery())
+				continue
+			}
+			filter = append(filter, v.toQuery())
+		}
+		_bool := F{}
+		if len(filter) > 0 {
+			_bool["filter"] = filter
+		}
+		if len(mustnot) > 0 {
+			_bool["must_not"] = mustnot
+		}
+		return F{"bool": _bool}
+	case "or":
+		should := []F{}
+		for _, v := range n.nodes {
+			should = append(should, v.toQuery())
+		}
+		return F{"bool": F{"should": should, "minimum_should_match": 1}}
+	case "not":
+		return F{"bool": F{"must_not": []F{n.nodes[0].toQuery()}}}
+	}
+	return n.query
+}
+
+// where puts the conditions on client, the top level AND conditions are put into filter and must_not
+func (n *sqlNode) where(c *Client) {
+	nodes := []*sqlNode{n}
+	if n.op == "and" {
+		nodes = n.nodes
+	}
+	for _, v := range nodes {
+		if v.op == "not" {
+			c.mustnot = append(c.mustnot, Not(v.nodes[0].toQuery()))
+			continue
+		}
+		c.filter = append(c.filter, v.toQuery())
+	}
+}
+
+// orderLimit parses ORDER BY field [ASC|DESC], ... and LIMIT [offset,] size | LIMIT size OFFSET offset
+func (p *sqlParser) orderLimit(c *Client) {
+	if p.acceptKeyword("ORDER") {
+		p.expectKeyword("BY")
+		for {
+			field := p.ident()
+			order := Asc
+			if p.acceptKeyword("DESC") {
+				order = Desc
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			c.ThenBy(field, order)
+			if !p.acceptPunct(",") {
+				break
+			}
+		}
+	}
+
+	if offset, size := p.limit(); offset > 0 {
+		c.Limit(offset, size)
+	} else if size >= 0 {
+		c.Limit(size)
+	}
+}
+
+// limit parses LIMIT [offset,] size | LIMIT size OFFSET offset, size is -1 without LIMIT
+func (p *sqlParser) limit() (offset, size int) {
+	if !p.acceptKeyword("LIMIT") {
+		return 0, -1
+	}
+	size = p.integer()
+	if p.acceptPunct(",") {
+		offset, size = size, p.integer()
+	} else if p.acceptKeyword("OFFSET") {
+		offset = p.integer()
+	}
+	return offset, size
+}
+
+func (p *sqlParser) end() {
+	if p.peek().kind != sqlEOF {
+		p.unexpected("end of input")
+	}
+	if p.nArg != len(p.args) {
+		sqlFail("%d args for %d placeholders", len(p.args), p.nArg)
+	}
+}
+
+// SQL sets conditions, sorts and limit of client by a sql-like string:
+// [WHERE] condition [ORDER BY field [ASC|DESC], ...] [LIMIT [offset,] size]
+// conditions: = != <> > >= < <= [NOT] IN (...) IS [NOT] NULL [NOT] LIKE [NOT] BETWEEN ... AND ...
+// combined by AND OR NOT and (). ? is a placeholder bound to args in order, args are set into query
+// as values, never spliced into the sql, a slice arg of IN (?) is expanded.
+// e.g  SQL("age > ? AND (name = 'bob' OR tags IN (?)) AND deleted_at IS NULL ORDER BY created_at DESC LIMIT 10, 20", 18, []string{"a", "b"})
+// =: term, >: range, IN: terms, IS NULL: exists, LIKE: wildcard
+func (c *Client) SQL(query string, args ...interface{}) *Client {
+	if c.Error != nil {
+		return c
+	}
+	c.Error = catchSQL(func() {
+		p := newSQLParser(query, args)
+		p.acceptKeyword("WHERE")
+		if !p.isKeyword("ORDER", "LIMIT") && p.peek().kind != sqlEOF {
+			p.expr().where(c)
+		}
+		p.orderLimit(c)
+		p.end()
+	})
+	return c
+}
@@ -0,0 +1,102 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestSQLTemplate(t *testing.T) {
+	cases := []struct {
+		sql  string
+		args []interface{}
+		want string
+	}{
+		{
+			sql:  "Level > 1 AND (Name = 'bob' OR Level IN (?)) AND Description IS NULL ORDER BY JoinDate DESC LIMIT 10, 20",
+			args: []interface{}{[]int{4, 5}},
+			want: `{"from":10,"query":{"bool":{"filter":[{"range":{"Level":{"gt":1}}},{"bool":{"minimum_should_match":1,"should":[{"term":{"Name":"bob"}},{"terms":{"Level":[4,5]}}]}}],"must_not":[{"exists":{"field":"Description"}}]}},"size":20,"sort":[{"JoinDate":{"order":"desc"}}]}`,
+		},
+		{
+			sql:  "WHERE Level BETWEEN ? AND ? AND Name NOT LIKE 'b_b%*' AND Number != -3 ORDER BY Level LIMIT 5 OFFSET 10",
+			args: []interface{}{1, 5},
+			want: `{"from":10,"query":{"bool":{"filter":[{"range":{"Level":{"gte":1,"lte":5}}}],"must_not":[{"wildcard":{"Name":"b?b*\\*"}},{"term":{"Number":-3}}]}},"size":5,"sort":[{"Level":{"order":"asc"}}]}`,
+		},
+		{
+			sql:  "`order` = 'it''s' OR NOT Enable = TRUE",
+			want: `{"query":{"bool":{"filter":[{"bool":{"minimum_should_match":1,"should":[{"term":{"order":"it's"}},{"bool":{"must_not":[{"term":{"Enable":true}}]}}]}}]}}}`,
+		},
+		{
+			sql:  "Name = ?",
+			args: []interface{}{`" OR 1=1 --`},
+			want: `{"query":{"bool":{"filter":[{"term":{"Name":"\" OR 1=1 --"}}]}}}`,
+		},
+	}
+
+	for _, v := range cases {
+		c := es.DB().SQL(v.sql, v.args...)
+		if err := c.Serialize().Error; err != nil {
+			t.Fatal(v.sql, err)
+		}
+		if c.Template() != v.want {
+			t.Fatal(v.sql, c.Template())
+		}
+	}
+}
+
+func TestSQLError(t *testing.T) {
+	cases := []struct {
+		sql  string
+		args []interface{}
+	}{
+		{sql: "Name = "},
+		{sql: "Name = 1 AND"},
+		{sql: "Name == 1"},
+		{sql: "Name = ?"},
+		{sql: "Name = 1", args: []interface{}{1}},
+		{sql: "Name = 'x"},
+		{sql: "(Name = 1"},
+		{sql: "order = 1"},
+		{sql: "Level > NULL"},
+		{sql: "Name = 1 LIMIT x"},
+	}
+
+	for _, v := range cases {
+		if err := es.DB().SQL(v.sql, v.args...).Error; err == nil {
+			t.Fatal(v.sql)
+		} else {
+			t.Log(err)
+		}
+	}
+}
+
+func TestSQLSearch(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "this is a test", Level: 2, Number: 1, Description: "described"},
+		mysql{Name: "another one", Level: 3, Number: 1},
+	)
+
+	cases := []struct {
+		sql  string
+		args []interface{}
+		want int64
+	}{
+		{sql: "Number = 1 AND Level >= ?", args: []interface{}{2}, want: 2},
+		{sql: "Number = 1 AND (Level = 1 OR Level = 3)", want: 2},
+		{sql: "Number = 1 AND Description IS NOT NULL", want: 1},
+		{sql: "Number = 1 AND NOT Level IN (1, 2)", want: 1},
+		{sql: "Number = 1 AND Description LIKE 'desc%'", want: 1},
+	}
+
+	for _, v := range cases {
+		got, err := es.DB().SQL(v.sql, v.args...).CountDocs()
+		if err != nil {
+			t.Fatal(v.sql, err)
+		}
+		if got != v.want {
+			t.Fatal(v.sql, v.want, got)
+		}
+	}
+}
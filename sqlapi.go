@@ -0,0 +1,196 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var version struct {
+	sync.Mutex
+	number string
+}
+
+// ServerVersion returns the version number of elasticsearch, e.g "6.5.4". it's detected once.
+// https://localhost:9200/
+func ServerVersion() (string, error) {
+	version.Lock()
+	defer version.Unlock()
+	if version.number != "" {
+		return version.number, nil
+	}
+
+	var info struct {
+		Version struct {
+			Number string `json:"number"`
+		} `json:"version"`
+	}
+	c := DB("")
+	if c.exec(c.hostDB.String()).Error != nil {
+		return "", c.Error
+	}
+	if c.Response(&info); c.Error != nil {
+		return "", c.Error
+	}
+	if info.Version.Number == "" {
+		return "", fmt.Errorf("esql: unknown elasticsearch version")
+	}
+	version.number = info.Version.Number
+	return version.number, nil
+}
+
+// sqlEndpoint _xpack/sql before 7.0, _sql after
+func sqlEndpoint() (string, error) {
+	number, err := ServerVersion()
+	if err != nil {
+		return "", err
+	}
+	major, _ := strconv.Atoi(strings.SplitN(number, ".", 2)[0])
+	if major < 7 {
+		return "_xpack/sql", nil
+	}
+	return "_sql", nil
+}
+
+// FetchSize the max rows of every page of RunSQL, 1000 by default
+func (c *Client) FetchSize(size int) *Client {
+	c.search["fetch_size"] = size
+	return c
+}
+
+// TimeZone the time zone of RunSQL, e.g "Europe/Paris"
+func (c *Client) TimeZone(zone string) *Client {
+	c.search["time_zone"] = zone
+	return c
+}
+
+// sqlResponse a page of _sql
+type sqlResponse struct {
+	Columns []Column        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Cursor  string          `json:"cursor"`
+}
+
+// the settings of client which the SQL endpoint accepts, the others of search are not sent
+var sqlSettings = []string{"fetch_size", "time_zone", "request_timeout", "page_timeout"}
+
+// RunSQL runs query on the SQL endpoint of elasticsearch, follows the cursor till the last page.
+// the conditions of client are sent as filter, params replace the ? of query in order (6.7+)
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/sql-rest.html
+// e.g DB("").Term(F{"Level": 1}).RunSQL("SELECT Name FROM esql WHERE Number = ?", 1)
+func (c *Client) RunSQL(query string, params ...interface{}) (*Table, error) {
+	endpoint, err := sqlEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	body := F{"query": query}
+	for _, k := range sqlSettings {
+		if v, ok := c.search[k]; ok {
+			body[k] = v
+		}
+	}
+	if q := c.Query(); q != nil {
+		if c.strict {
+			data, _ := json.Marshal(F{"query": q})
+			if c.Error = c.validate(data); c.Error != nil {
+				return nil, c.Error
+			}
+		}
+		body["filter"] = q
+	}
+	if len(params) > 0 {
+		body["params"] = params
+	}
+	c.clear()
+
+	var page sqlResponse
+	if err := c.postSQL(endpoint, body, &page); err != nil {
+		return nil, err
+	}
+
+	table := &Table{Columns: page.Columns, Rows: page.Rows}
+	for page.Cursor != "" {
+		cursor := page.Cursor
+		page = sqlResponse{}
+		if err := c.postSQL(endpoint, F{"cursor": cursor}, &page); err != nil {
+			c.closeCursor(endpoint, cursor)
+			return nil, err
+		}
+		table.Rows = append(table.Rows, page.Rows...)
+	}
+	return table, nil
+}
+
+// Translate returns the query DSL of a sql
+// https://www.elastic.co/guide/en/elasticsearch/reference/6.5/sql-translate.html
+func (c *Client) Translate(query string) (F, error) {
+	endpoint, err := sqlEndpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	var dsl F
+	if err := c.postSQL(endpoint+"/translate", F{"query": query}, &dsl); err != nil {
+		return nil, err
+	}
+	return dsl, nil
+}
+
+// closeCursor releases the cursor on server, it's unnecessary after the last page
+func (c *Client) closeCursor(endpoint, cursor string) error {
+	c.Error = nil
+	return c.postSQL(endpoint+"/close", F{"cursor": cursor}, nil)
+}
+
+func (c *Client) postSQL(endpoint string, body F, result interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	c.method, c.hostDB.Path = "POST", endpoint
+	c.hostDB.RawQuery = "format=json"
+	c.template = string(data)
+	if c.exec(c.hostDB.String(), c.template).Error != nil {
+		return c.Error
+	}
+	c.Response(result)
+	return c.Error
+}
+
+// Scan decodes rows into i by column name, i is a pointer to slice of struct or map.
+// dotted column names are the nested objects, e.g "address.city"
+func (t *Table) Scan(i interface{}) error {
+	objs := make([]F, len(t.Rows))
+	for r, row := range t.Rows {
+		obj := F{}
+		for n, col := range t.Columns {
+			if n < len(row) {
+				setPath(obj, col.Name, row[n])
+			}
+		}
+		objs[r] = obj
+	}
+
+	data, err := json.Marshal(objs)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, i)
+}
+
+func setPath(obj F, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := obj[k].(F)
+		if !ok {
+			next = F{}
+			obj[k] = next
+		}
+		obj = next
+	}
+	obj[keys[len(keys)-1]] = value
+}
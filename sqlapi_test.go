@@ -0,0 +1,62 @@
+package esql_test
+
+import (
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestTableScan(t *testing.T) {
+	table := esql.Table{
+		Columns: []esql.Column{{Name: "Name"}, {Name: "Level"}, {Name: "Golang.First"}},
+		Rows: [][]interface{}{
+			{"this is a test", float64(1), "go"},
+			{"another one", float64(2), nil},
+		},
+	}
+
+	var records []struct {
+		Name   string
+		Level  int
+		Golang golang
+	}
+	if err := table.Scan(&records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Level != 1 || records[0].Golang.First != "go" || records[1].Name != "another one" {
+		t.Fatal(records)
+	}
+}
+
+func TestRunSQL(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "this is a test", Level: 2, Number: 1},
+		mysql{Name: "another one", Level: 3, Number: 1},
+	)
+
+	// a page of 2 rows, the last row is from the cursor, the settings of search are not sent
+	table, err := esql.DB("").FetchSize(2).Limit(10).TrackTotalHits(true).Range(esql.F{"Level": esql.F{"gte": 1}}).
+		RunSQL("SELECT Name, Level FROM esql WHERE Number = 1 ORDER BY Level")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(table)
+
+	var records []mysql
+	if err := table.Scan(&records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[2].Level != 3 || records[2].Name != "another one" {
+		t.Fatal(records)
+	}
+
+	dsl, err := esql.DB("").Translate("SELECT Name FROM esql WHERE Level > 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dsl["query"]; !ok {
+		t.Fatal(dsl)
+	}
+}
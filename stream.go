@@ -0,0 +1,141 @@
+package esql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"reflect"
+)
+
+// KeepResponse keeps the raw response of FindEach and Find into slice, to read it by Response.
+// it's not kept by default, as a large response takes memory twice with the decoded hits.
+func (c *Client) KeepResponse() *Client {
+	c.keep = true
+	return c
+}
+
+// FindEach searches, and decodes the hits one by one from the response stream, fn is called on every hit.
+// the whole response is not in memory, the result returned has the other parts of response without hits.
+// it stops on the first error of fn.
+// e.g es.DB().Term(F{"Level": 1}).Limit(10000).FindEach(func(h Hit) error { return h.Decode(&doc) })
+func (c *Client) FindEach(fn func(h Hit) error) (*SearchResult, error) {
+	c.Serialize()
+	c.hostDB.Path = path.Join(c.hostDB.Path, "_search")
+	resp := c.send(c.uri(), c.template)
+	if resp == nil {
+		return nil, c.Error
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	var raw bytes.Buffer
+	if c.keep {
+		body = io.TeeReader(resp.Body, &raw)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		data, _ := ioutil.ReadAll(body)
+		c.Error = fmt.Errorf("%s", data)
+		return nil, c.Error
+	}
+
+	result, err := decodeStream(body, fn)
+	if c.keep {
+		c.response = raw.Bytes()
+	}
+	c.Error = err
+	return result, err
+}
+
+// decodeStream decodes the search response, the hits.hits are passed to fn one by one
+func decodeStream(r io.Reader, fn func(h Hit) error) (*SearchResult, error) {
+	dec := json.NewDecoder(r)
+	envelope, hits := map[string]json.RawMessage{}, map[string]json.RawMessage{}
+
+	err := decodeObject(dec, func(key string) error {
+		if key != "hits" {
+			var v json.RawMessage
+			err := dec.Decode(&v)
+			envelope[key] = v
+			return err
+		}
+		return decodeObject(dec, func(key string) error {
+			if key != "hits" {
+				var v json.RawMessage
+				err := dec.Decode(&v)
+				hits[key] = v
+				return err
+			}
+			if err := expectDelim(dec, '['); err != nil {
+				return err
+			}
+			for dec.More() {
+				var h Hit
+				if err := dec.Decode(&h); err != nil {
+					return err
+				}
+				if err := fn(h); err != nil {
+					return err
+				}
+			}
+			return expectDelim(dec, ']')
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := json.Marshal(hits)
+	envelope["hits"] = data
+	data, _ = json.Marshal(envelope)
+	var result SearchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// decodeObject reads a json object, fn decodes the value of key
+func decodeObject(dec *json.Decoder, fn func(key string) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("esql: unexpected %v in response", t)
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("esql: expected %v but %v in response", delim, t)
+	}
+	return nil
+}
+
+// findSlice Find into a pointer to slice of struct, the hits are decoded by FindEach
+func (c *Client) findSlice(i interface{}) *Client {
+	slice := reflect.ValueOf(i).Elem()
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	c.FindEach(func(h Hit) error {
+		return appendHit(slice, h)
+	})
+	return c
+}
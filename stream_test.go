@@ -0,0 +1,48 @@
+package esql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestFindEach(t *testing.T) {
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(
+		mysql{Name: "this is a test", Level: 1, Number: 1},
+		mysql{Name: "another one", Level: 2, Number: 1},
+		mysql{Name: "the last", Level: 3, Number: 1},
+	)
+
+	levels := []int{}
+	result, err := es.DB().Term(esql.F{"Number": 1}).OrderBy("Level").Group("Level").FindEach(func(h esql.Hit) error {
+		var record mysql
+		if err := h.Decode(&record); err != nil {
+			return err
+		}
+		levels = append(levels, record.Level)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(levels) != 3 || levels[2] != 3 || result.Total.Value != 3 || len(result.Hits) != 0 || result.Aggregations["group_Level"] == nil {
+		t.Fatal(levels, result)
+	}
+
+	// the response is kept only when it's requested
+	c := es.DB().Term(esql.F{"Number": 1})
+	if _, err := c.FindEach(func(h esql.Hit) error { return nil }); err != nil || c.Response(nil) != nil {
+		t.Fatal(err, string(c.Response(nil)))
+	}
+	c = es.DB().Term(esql.F{"Number": 1}).KeepResponse()
+	if _, err := c.FindEach(func(h esql.Hit) error { return nil }); err != nil || len(c.Response(nil)) == 0 {
+		t.Fatal(err)
+	}
+
+	stop := errors.New("stop")
+	if _, err := es.DB().FindEach(func(h esql.Hit) error { return stop }); err != stop {
+		t.Fatal(err)
+	}
+}
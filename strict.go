@@ -0,0 +1,366 @@
+package esql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// the field types of index mappings, cached by index
+var mappings = struct {
+	sync.Mutex
+	m map[string]map[string]string
+}{m: map[string]map[string]string{}}
+
+// Strict validates the fields referenced by the query, sort, aggregations, _source etc. against the mapping
+// of index before sending, the unknown fields and type mismatches (e.g term on text, range on keyword)
+// are reported as Error. the mapping is fetched by ShowMapping and cached, it is fetched again
+// when a field is unknown, as the dynamic fields may be added after.
+// the query of CountDocs, Exists, ExplainDoc and the filter of RunSQL are validated too.
+// e.g es.DB().Strict().Where(F{"Nmae": "x"}).Find(&result) // unknown field "Nmae"
+func (c *Client) Strict() *Client {
+	c.strict = true
+	return c
+}
+
+// validate checks the fields of search body data
+func (c *Client) validate(data []byte) error {
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+
+	index := indexPath(c.hostDB.Path)
+	fields, cached, err := indexFields(index, false)
+	if err != nil {
+		return err
+	}
+
+	problems := checkBody(fields, body)
+	if len(problems) > 0 && cached {
+		if fields, _, err = indexFields(index, true); err != nil {
+			return err
+		}
+		problems = checkBody(fields, body)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("esql: strict: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// indexPath the index of path, the api after it is cut, e.g "esql/_delete_by_query" is esql.
+// the names of index can't start with _
+func indexPath(p string) string {
+	names := []string{}
+	for _, v := range strings.Split(strings.Trim(p, "/"), "/") {
+		if strings.HasPrefix(v, "_") {
+			break
+		}
+		names = append(names, v)
+	}
+	return strings.Join(names, "/")
+}
+
+// indexFields the types of fields by dotted path, the multi fields are included, e.g "Name.keyword"
+func indexFields(index string, refresh bool) (map[string]string, bool, error) {
+	mappings.Lock()
+	defer mappings.Unlock()
+	if fields, ok := mappings.m[index]; ok && !refresh {
+		return fields, true, nil
+	}
+
+	var resp map[string]struct {
+		Mappings map[string]json.RawMessage `json:"mappings"`
+	}
+	c := DB(index).ShowMapping()
+	if c.Error != nil {
+		return nil, false, c.Error
+	}
+	if c.Response(&resp); c.Error != nil {
+		return nil, false, c.Error
+	}
+
+	fields := map[string]string{}
+	for _, idx := range resp {
+		// 7.x {"properties": {}}, 6.x {"_doc": {"properties": {}}}
+		if props, ok := idx.Mappings["properties"]; ok {
+			mappingFields(fields, "", props)
+			continue
+		}
+		for _, doc := range idx.Mappings {
+			var m struct {
+				Properties json.RawMessage `json:"properties"`
+			}
+			if json.Unmarshal(doc, &m) == nil && m.Properties != nil {
+				mappingFields(fields, "", m.Properties)
+			}
+		}
+	}
+	mappings.m[index] = fields
+	return fields, false, nil
+}
+
+func mappingFields(fields map[string]string, prefix string, data json.RawMessage) {
+	var props map[string]struct {
+		Type       string                     `json:"type"`
+		Properties json.RawMessage            `json:"properties"`
+		Fields     map[string]json.RawMessage `json:"fields"`
+	}
+	if json.Unmarshal(data, &props) != nil {
+		return
+	}
+
+	for name, p := range props {
+		types := p.Type
+		if types == "" {
+			types = "object"
+		}
+		fields[prefix+name] = types
+		if p.Properties != nil {
+			mappingFields(fields, prefix+name+".", p.Properties)
+		}
+		if p.Fields != nil {
+			raw, _ := json.Marshal(p.Fields)
+			mappingFields(fields, prefix+name+".", raw)
+		}
+	}
+}
+
+// forgetMapping drops the cached mapping of index, after the mapping is changed
+func forgetMapping(index string) {
+	mappings.Lock()
+	delete(mappings.m, strings.Trim(index, "/"))
+	mappings.Unlock()
+}
+
+// the queries whose keys are fields, except the options
+var fieldQueries = map[string]bool{
+	"match": true, "match_phrase": true, "match_phrase_prefix": true, "match_bool_prefix": true,
+	"term": true, "terms": true, "range": true, "prefix": true, "wildcard": true, "regexp": true, "fuzzy": true,
+	"span_term": true, "geo_distance": true, "geo_bounding_box": true, "geo_polygon": true, "geo_shape": true,
+}
+
+// the options of fieldQueries
+var queryOptions = map[string]bool{
+	"boost": true, "_name": true, "distance": true, "distance_type": true, "validation_method": true,
+	"type": true, "ignore_unmapped": true, "unit": true,
+}
+
+var rangeTypes = map[string]bool{
+	"long": true, "integer": true, "short": true, "byte": true, "double": true, "float": true, "half_float": true,
+	"scaled_float": true, "date": true, "date_nanos": true, "ip": true, "integer_range": true, "float_range": true,
+	"long_range": true, "double_range": true, "date_range": true, "ip_range": true,
+}
+
+// the queries made of sub queries
+var compoundQueries = map[string][]string{
+	"bool":            {"must", "should", "filter", "must_not"},
+	"dis_max":         {"queries"},
+	"constant_score":  {"filter"},
+	"boosting":        {"positive", "negative"},
+	"function_score":  {"query"},
+	"nested":          {"query"},
+	"has_child":       {"query"},
+	"has_parent":      {"query"},
+	"span_near":       {"clauses"},
+	"span_or":         {"clauses"},
+	"span_not":        {"include", "exclude"},
+	"span_first":      {"match"},
+	"span_containing": {"big", "little"},
+	"span_within":     {"big", "little"},
+}
+
+type checker struct {
+	fields   map[string]string
+	problems map[string]bool
+}
+
+func checkBody(fields map[string]string, body map[string]interface{}) []string {
+	ck := &checker{fields: fields, problems: map[string]bool{}}
+	ck.query(body["query"])
+	ck.query(body["post_filter"])
+	ck.sort(body["sort"])
+	ck.aggs(body["aggs"])
+	ck.source(body["_source"])
+	for _, k := range []string{"stored_fields", "docvalue_fields"} {
+		for _, v := range list(body[k]) {
+			if m, ok := asMap(v); ok {
+				v = m["field"]
+			}
+			if s, ok := v.(string); ok {
+				ck.field(s)
+			}
+		}
+	}
+
+	problems := make([]string, 0, len(ck.problems))
+	for p := range ck.problems {
+		problems = append(problems, p)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// field reports the field if it is unknown, returns the type of field
+func (ck *checker) field(name string) string {
+	if name == "" || strings.HasPrefix(name, "_") || strings.ContainsAny(name, "*?") {
+		return ""
+	}
+	types, ok := ck.fields[name]
+	if !ok {
+		ck.problems[fmt.Sprintf("unknown field %q", name)] = true
+	}
+	return types
+}
+
+func (ck *checker) query(i interface{}) {
+	for _, q := range list(i) {
+		m, ok := asMap(q)
+		if !ok {
+			continue
+		}
+		for types, v := range m {
+			setting, _ := asMap(v)
+			switch {
+			case fieldQueries[types]:
+				for name := range setting {
+					if queryOptions[name] {
+						continue
+					}
+					ck.mismatch(types, name, ck.field(name))
+				}
+			case compoundQueries[types] != nil:
+				if types == "nested" {
+					if s, ok := setting["path"].(string); ok {
+						ck.field(s)
+					}
+				}
+				for _, k := range compoundQueries[types] {
+					ck.query(setting[k])
+				}
+			case types == "exists":
+				if s, ok := setting["field"].(string); ok {
+					ck.field(s)
+				}
+			case types == "multi_match" || types == "query_string" || types == "simple_query_string":
+				for _, f := range list(setting["fields"]) {
+					if s, ok := f.(string); ok {
+						ck.field(strings.SplitN(s, "^", 2)[0])
+					}
+				}
+			case types == "span_multi":
+				ck.query(setting["match"])
+			}
+		}
+	}
+}
+
+func (ck *checker) mismatch(query, field, types string) {
+	if types == "" {
+		return
+	}
+	switch {
+	case (query == "term" || query == "terms") && types == "text":
+		ck.problems[fmt.Sprintf("%s on text field %q", query, field)] = true
+	case query == "range" && !rangeTypes[types]:
+		ck.problems[fmt.Sprintf("range on %s field %q", types, field)] = true
+	}
+}
+
+func (ck *checker) sort(i interface{}) {
+	for _, s := range list(i) {
+		if name, ok := s.(string); ok {
+			ck.field(name)
+			continue
+		}
+		m, _ := asMap(s)
+		for name, v := range m {
+			if name != "_geo_distance" {
+				ck.field(name)
+				continue
+			}
+			setting, _ := asMap(v)
+			for k := range setting {
+				switch k {
+				case "order", "unit", "mode", "distance_type", "ignore_unmapped", "nested":
+				default:
+					ck.field(k)
+				}
+			}
+		}
+	}
+}
+
+func (ck *checker) aggs(i interface{}) {
+	aggs, _ := asMap(i)
+	for _, v := range aggs {
+		agg, _ := asMap(v)
+		for types, s := range agg {
+			switch types {
+			case "aggs", "aggregations":
+				ck.aggs(s)
+				continue
+			case "meta":
+				continue
+			case "filter":
+				ck.query(s)
+				continue
+			case "filters":
+				setting, _ := asMap(s)
+				filters := setting["filters"]
+				if m, ok := asMap(filters); ok {
+					for _, q := range m {
+						ck.query(q)
+					}
+				} else {
+					ck.query(filters)
+				}
+				continue
+			}
+
+			setting, _ := asMap(s)
+			if name, ok := setting["field"].(string); ok {
+				if ck.field(name) == "text" && types != "significant_text" {
+					ck.problems[fmt.Sprintf("%s aggregation on text field %q", types, name)] = true
+				}
+			}
+			if types == "nested" {
+				if name, ok := setting["path"].(string); ok {
+					ck.field(name)
+				}
+			}
+		}
+	}
+}
+
+func (ck *checker) source(i interface{}) {
+	if m, ok := asMap(i); ok {
+		ck.source(m["includes"])
+		ck.source(m["excludes"])
+		return
+	}
+	for _, v := range list(i) {
+		if s, ok := v.(string); ok {
+			ck.field(s)
+		}
+	}
+}
+
+// list makes a value or an array of values as array
+func list(i interface{}) []interface{} {
+	switch v := i.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	}
+	return []interface{}{i}
+}
+
+func asMap(i interface{}) (map[string]interface{}, bool) {
+	m, ok := i.(map[string]interface{})
+	return m, ok
+}
@@ -0,0 +1,51 @@
+package esql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/han2015/esql"
+)
+
+func TestStrict(t *testing.T) {
+	cases := []struct {
+		c    *esql.Client
+		want string
+	}{
+		{es.DB().Strict().Where(esql.F{"Nmae": "x"}), `unknown field "Nmae"`},
+		{es.DB().Strict().Term(esql.F{"Name": "x"}), `term on text field "Name"`},
+		{es.DB().Strict().Range(esql.F{"Description": esql.F{"gt": "a"}}), `range on keyword field "Description"`},
+		{es.DB().Strict().OrderBy("Agee"), `unknown field "Agee"`},
+		{es.DB().Strict().Group("Golang.Frist"), `unknown field "Golang.Frist"`},
+		{es.DB().Strict().Avg("Name"), `avg aggregation on text field "Name"`},
+		{es.DB().Strict().Source([]string{"Name", "Numbr"}), `unknown field "Numbr"`},
+		{es.DB().Strict().Clause("must", esql.Nested("As3", es.DB().Term(esql.F{"As3.Nam": "go"}).Query())), `unknown field "As3.Nam"`},
+	}
+	for _, v := range cases {
+		err := v.c.Serialize().Error
+		if err == nil || !strings.Contains(err.Error(), v.want) {
+			t.Fatal(v.want, err)
+		}
+	}
+
+	// the apis out of Serialize, and the apis which change the path before it
+	_, err := es.DB().Strict().Term(esql.F{"Nmae": "x"}).CountDocs()
+	errs := []error{
+		err,
+		es.DB().Strict().Term(esql.F{"Nmae": "x"}).ExplainDoc("1").Error,
+		es.DB().Strict().Term(esql.F{"Nmae": "x"}).DeleteByQuerry().Error,
+		es.DB().Strict().Term(esql.F{"Nmae": "x"}).ValidateQuery().Error,
+	}
+	for _, err := range errs {
+		if err == nil || !strings.Contains(err.Error(), `unknown field "Nmae"`) {
+			t.Fatal(err)
+		}
+	}
+
+	c := es.DB().Strict().Where(esql.F{"Name": "go"}).Term(esql.F{"Number": 1}).Range(esql.F{"Age": esql.F{"gt": 1}}).
+		Clause("must", esql.Nested("As3", es.DB().Term(esql.F{"As3.Name": "go"}).Query())).
+		OrderBy("JoinDate", esql.Desc).Group("Description").Max("Age", "group_Description").Source("Golang.*")
+	if err := c.Serialize().Error; err != nil {
+		t.Fatal(err)
+	}
+}
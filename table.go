@@ -0,0 +1,171 @@
+package esql
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Table a tabular result of rows and columns, see Query and Client.Rows
+type Table struct {
+	Columns []Column        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// Column name and type of a table column
+type Column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// typeColumns sets the empty types of columns by the first non-nil value
+func (t *Table) typeColumns() {
+	for i := range t.Columns {
+		if t.Columns[i].Type != "" {
+			continue
+		}
+		for _, row := range t.Rows {
+			if row[i] != nil {
+				t.Columns[i].Type = valueType(row[i])
+				break
+			}
+		}
+	}
+}
+
+func valueType(v interface{}) string {
+	switch n := v.(type) {
+	case string:
+		return "keyword"
+	case bool:
+		return "boolean"
+	case int64, int:
+		return "long"
+	case float64:
+		if n == float64(int64(n)) {
+			return "long"
+		}
+		return "double"
+	case json.Number:
+		if _, err := n.Int64(); err == nil {
+			return "long"
+		}
+		return "double"
+	}
+	return "object"
+}
+
+// sourceValue gets the value of dotted path in source, e.g "address.city", values in arrays are collected.
+func sourceValue(source interface{}, path string) interface{} {
+	if path == "" {
+		return source
+	}
+	key, rest := path, ""
+	if i := strings.Index(path, "."); i >= 0 {
+		key, rest = path[:i], path[i+1:]
+	}
+
+	switch v := source.(type) {
+	case map[string]interface{}:
+		// the field name itself may contain dots
+		if value, ok := v[path]; ok {
+			return value
+		}
+		return sourceValue(v[key], rest)
+	case []interface{}:
+		arr := []interface{}{}
+		for _, e := range v {
+			if value := sourceValue(e, path); value != nil {
+				arr = append(arr, value)
+			}
+		}
+		if len(arr) == 0 {
+			return nil
+		}
+		return arr
+	}
+	return nil
+}
+
+// sourcePaths the dotted paths of all leaf values in source, in order of name
+func sourcePaths(prefix string, source map[string]interface{}) (paths []string) {
+	for _, k := range sortedKeys(source) {
+		if m, ok := source[k].(map[string]interface{}); ok && len(m) > 0 {
+			paths = append(paths, sourcePaths(prefix+k+".", m)...)
+			continue
+		}
+		paths = append(paths, prefix+k)
+	}
+	return paths
+}
+
+// compareValues orders nil, bool, numbers and strings
+func compareValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a == b:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+
+	fa, okA := toFloat(a)
+	fb, okB := toFloat(b)
+	if okA && okB {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+
+	return strings.Compare(toString(a), toString(b))
+}
+
+// toFloat the value of numbers of all kinds and json.Number
+func toFloat(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// sortRows sorts rows stably by the columns, desc[i] for columns[i]
+func sortRows(rows [][]interface{}, columns []int, desc []bool) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for n, col := range columns {
+			r := compareValues(rows[i][col], rows[j][col])
+			if desc[n] {
+				r = -r
+			}
+			if r != 0 {
+				return r < 0
+			}
+		}
+		return false
+	})
+}
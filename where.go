@@ -0,0 +1,101 @@
+package esql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// the range operators of tag op, e.g `esql:"type:integer;op:gte"`
+var rangeOps = map[string]bool{"gt": true, "gte": true, "lt": true, "lte": true}
+
+// WhereStruct query by example, the non-zero fields of struct i are the conditions, the same as gorm's Where.
+// the queries are made by the mapped types of fields, see AutoMapping:
+// text as Where (match), keyword, numeric, boolean and date as Term, the fields tagged op:gt/gte/lt/lte as Range,
+// slices as Terms (Where of the joined values for text), nested structs are dotted fields, e.g "Golang.First".
+// the fields of pointer are conditions when they are not nil, even if they point to zero values.
+// e.g WhereStruct(mysql{Name: "test", Level: 2})
+func (c *Client) WhereStruct(i interface{}) *Client {
+	v := reflect.ValueOf(i)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		c.Error = fmt.Errorf("esql: WhereStruct needs a struct, but got %T", i)
+		return c
+	}
+
+	c.whereStruct("", v, parseStruct(v.Type()))
+	return c
+}
+
+func (c *Client) whereStruct(prefix string, v reflect.Value, mapping F) {
+	t := v.Type()
+	for n := 0; n < t.NumField(); n++ {
+		fd, fv := t.Field(n), v.Field(n)
+		tags := fd.Tag.Get("esql")
+		if _, meta := metaFields[tags]; fd.PkgPath != "" || tags == "-" || meta {
+			continue
+		}
+
+		set := false
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv, set = fv.Elem(), true
+		}
+		if !set && fv.IsZero() {
+			continue
+		}
+
+		field := prefix + fd.Name
+		_set, _ := mapping[fd.Name].(F)
+		types, _ := _set["type"].(string)
+		if types == "geo_point" || types == "geo_shape" {
+			continue
+		}
+
+		if op, _ := parseTags(tags)["op"].(string); rangeOps[op] {
+			c.filter = append(c.filter, c.named("range", F{field: F{op: fv.Interface()}}))
+			continue
+		}
+
+		switch {
+		case fv.Kind() == reflect.Struct && types != "date":
+			props, _ := _set["properties"].(F)
+			c.whereStruct(field+".", fv, props)
+		case fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array:
+			c.whereSlice(field, types, fv)
+		case fv.Kind() == reflect.Map:
+		case types == "text":
+			c.must = append(c.must, c.named("match", F{field: fv.Interface()}))
+		default:
+			c.filter = append(c.filter, c.named("term", F{field: fv.Interface()}))
+		}
+	}
+}
+
+// whereSlice the values of slice as terms, structs in slice are skipped
+func (c *Client) whereSlice(field, types string, fv reflect.Value) {
+	values := []interface{}{}
+	words := []string{}
+	for n := 0; n < fv.Len(); n++ {
+		e := fv.Index(n)
+		for e.Kind() == reflect.Ptr && !e.IsNil() {
+			e = e.Elem()
+		}
+		switch e.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Ptr:
+			return
+		}
+		values = append(values, e.Interface())
+		words = append(words, fmt.Sprint(e.Interface()))
+	}
+
+	if types == "text" {
+		c.must = append(c.must, c.named("match", F{field: strings.Join(words, " ")}))
+		return
+	}
+	c.filter = append(c.filter, c.named("terms", F{field: values}))
+}
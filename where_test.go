@@ -0,0 +1,66 @@
+package esql_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/han2015/esql"
+)
+
+type example struct {
+	Name     string
+	Number   int `esql:"type:keyword"`
+	Level    int `esql:"type:integer;op:gte"`
+	Enabled  *bool
+	Tags     []string `esql:"type:keyword"`
+	Golang   golang
+	JoinDate time.Time
+	Location esql.GeoPoint
+	skipped  string
+}
+
+func TestWhereStruct(t *testing.T) {
+	off := false
+	c := es.DB().WhereStruct(example{
+		Name:    "test",
+		Number:  1,
+		Level:   2,
+		Enabled: &off,
+		Tags:    []string{"a", "b"},
+		Golang:  golang{Last: "pike"},
+	}).Serialize()
+	if c.Error != nil {
+		t.Fatal(c.Error)
+	}
+	want := `{"query":{"bool":{"filter":[{"term":{"Number":1}},{"range":{"Level":{"gte":2}}},{"term":{"Enabled":false}},` +
+		`{"terms":{"Tags":["a","b"]}}],"must":[{"match":{"Name":"test"}},{"match":{"Golang.Last":"pike"}}]}}}`
+	if c.Template() != want {
+		t.Fatal(c.Template())
+	}
+
+	// the op tag is not in mapping
+	mapping := esql.DB("esql_example").AutoMapping(example{})
+	if mapping.Error != nil {
+		t.Fatal(mapping.Error)
+	}
+	esql.DB("esql_example").Delete()
+	if strings.Contains(mapping.Template(), "op") || !strings.Contains(mapping.Template(), `"Enabled":{"type":"boolean"}`) {
+		t.Fatal(mapping.Template())
+	}
+
+	if es.DB().WhereStruct("name").Error == nil {
+		t.Fatal("WhereStruct needs a struct")
+	}
+
+	es.DB().Term(esql.F{"Number": 1}).DeleteByQuerry()
+	initRecords(mysql{Name: "this is a test", Level: 1, Number: 1}, mysql{Name: "another one", Level: 2, Number: 1})
+
+	var result esql.SearchResult
+	if err := es.DB().WhereStruct(&mysql{Name: "test", Number: 1}).Find(&result).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 1 {
+		t.Fatal(result.Hits)
+	}
+}